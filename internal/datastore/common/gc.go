@@ -43,6 +43,13 @@ var (
 		Help:      "The number of stale namespaces deleted by the datastore garbage collection.",
 	})
 
+	gcWindowStartGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "spicedb",
+		Subsystem: "datastore",
+		Name:      "gc_window_start_timestamp_seconds",
+		Help:      "The timestamp of the start of the GC window used by the most recent datastore garbage collection.",
+	})
+
 	gcFailureCounterConfig = prometheus.CounterOpts{
 		Namespace: "spicedb",
 		Subsystem: "datastore",
@@ -60,6 +67,7 @@ func RegisterGCMetrics() error {
 		gcRelationshipsCounter,
 		gcTransactionsCounter,
 		gcNamespacesCounter,
+		gcWindowStartGauge,
 		gcFailureCounter,
 	} {
 		if err := prometheus.Register(metric); err != nil {
@@ -178,7 +186,8 @@ func RunGarbageCollection(gc GarbageCollector, window, timeout time.Duration) er
 		return fmt.Errorf("error retrieving now: %w", err)
 	}
 
-	watermark, err := gc.TxIDBefore(ctx, now.Add(-1*window))
+	windowStart := now.Add(-1 * window)
+	watermark, err := gc.TxIDBefore(ctx, windowStart)
 	if err != nil {
 		return fmt.Errorf("error retrieving watermark: %w", err)
 	}
@@ -200,6 +209,7 @@ func RunGarbageCollection(gc GarbageCollector, window, timeout time.Duration) er
 	gcRelationshipsCounter.Add(float64(collected.Relationships))
 	gcTransactionsCounter.Add(float64(collected.Transactions))
 	gcNamespacesCounter.Add(float64(collected.Namespaces))
+	gcWindowStartGauge.Set(float64(windowStart.UnixNano()) / float64(time.Second))
 	gc.MarkGCCompleted()
 	return nil
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/authzed/spicedb/pkg/datastore"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	promclient "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/require"
 )
@@ -200,3 +201,18 @@ func TestGCFailureBackoffReset(t *testing.T) {
 	// the GC enough time to run.
 	require.Greater(t, gc.GetMetrics().markedCompleteCount, 20, "Next interval was not reset with backoff")
 }
+
+func TestGCRecordsWindowStart(t *testing.T) {
+	gc := newFakeGC(revisionErrorDeleter{})
+	window := 1 * time.Hour
+
+	before := time.Now()
+	require.NoError(t, RunGarbageCollection(&gc, window, 1*time.Minute))
+	after := time.Now()
+
+	// The window start is the current time at the start of the run, less the window.
+	windowStart := testutil.ToFloat64(gcWindowStartGauge)
+	require.GreaterOrEqual(t, windowStart, float64(before.Add(-window).UnixNano())/float64(time.Second))
+	require.LessOrEqual(t, windowStart, float64(after.Add(-window).UnixNano())/float64(time.Second))
+	require.True(t, gc.HasGCRun())
+}
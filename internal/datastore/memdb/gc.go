@@ -0,0 +1,151 @@
+package memdb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/authzed/spicedb/internal/datastore/common"
+	"github.com/authzed/spicedb/internal/datastore/revisions"
+	"github.com/authzed/spicedb/pkg/datastore"
+	corev1 "github.com/authzed/spicedb/pkg/proto/core/v1"
+)
+
+var _ common.GarbageCollector = (*memdbDatastore)(nil)
+
+const (
+	// minGCInterval and maxGCInterval bound the period between two garbage
+	// collection runs, which otherwise matches the GC window.
+	minGCInterval = 100 * time.Millisecond
+	maxGCInterval = 1 * time.Minute
+
+	gcTimeout = 1 * time.Minute
+)
+
+// RunGC removes the snapshots and changelog entries that have fallen outside
+// of the GC window, keeping everything needed to serve the head revision and
+// any revision within the window.
+//
+// Garbage collection also runs periodically in the background; this method
+// exists primarily for testing.
+func (mdb *memdbDatastore) RunGC() error {
+	if !mdb.gcEnabled() {
+		return nil
+	}
+
+	return common.RunGarbageCollection(mdb, time.Duration(-mdb.negativeGCWindow), gcTimeout)
+}
+
+func (mdb *memdbDatastore) gcEnabled() bool {
+	return mdb.negativeGCWindow != DisableGC.Nanoseconds()*-1
+}
+
+func (mdb *memdbDatastore) gcInterval() time.Duration {
+	return max(min(time.Duration(-mdb.negativeGCWindow), maxGCInterval), minGCInterval)
+}
+
+func (mdb *memdbDatastore) HasGCRun() bool {
+	return mdb.gcHasRun.Load()
+}
+
+func (mdb *memdbDatastore) MarkGCCompleted() {
+	mdb.gcHasRun.Store(true)
+}
+
+func (mdb *memdbDatastore) ResetGCCompleted() {
+	mdb.gcHasRun.Store(false)
+}
+
+func (mdb *memdbDatastore) Now(_ context.Context) (time.Time, error) {
+	return time.Now().UTC(), nil
+}
+
+func (mdb *memdbDatastore) TxIDBefore(_ context.Context, before time.Time) (datastore.Revision, error) {
+	return revisions.NewForTime(before), nil
+}
+
+func (mdb *memdbDatastore) DeleteBeforeTx(ctx context.Context, txID datastore.Revision) (common.DeletionCounts, error) {
+	oldest, ok := txID.(revisions.TimestampRevision)
+	if !ok {
+		return common.DeletionCounts{}, fmt.Errorf("unexpected revision type %T", txID)
+	}
+
+	for {
+		mdb.Lock()
+		if mdb.db == nil {
+			mdb.Unlock()
+			return common.DeletionCounts{}, fmt.Errorf("datastore has been closed")
+		}
+
+		// The changelog can only be modified once the active write transaction, if
+		// any, has completed. Wait for it rather than failing the run.
+		if mdb.activeWriteTxn == nil {
+			break
+		}
+		done := mdb.activeWriteTxnDone
+		mdb.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return common.DeletionCounts{}, ctx.Err()
+		case <-done:
+		}
+	}
+	defer mdb.Unlock()
+
+	// Keep the newest snapshot taken before the start of the window, as it holds the
+	// state for the revisions at the start of the window, and always keep the head.
+	firstInWindow := sort.Search(len(mdb.revisions), func(i int) bool {
+		return !mdb.revisions[i].revision.LessThan(oldest)
+	})
+	removedSnapshots := min(max(firstInWindow-1, 0), len(mdb.revisions)-1)
+	if removedSnapshots > 0 {
+		mdb.revisions = append([]snapshot(nil), mdb.revisions[removedSnapshots:]...)
+	}
+
+	removed, err := mdb.deleteChangelogBeforeLocalCallerMustLock(oldest)
+	if err != nil {
+		return removed, fmt.Errorf("failed to GC changelog: %w", err)
+	}
+
+	removed.Transactions = int64(removedSnapshots)
+	return removed, nil
+}
+
+// deleteChangelogBeforeLocalCallerMustLock deletes the changelog entries at
+// revisions before the given one, counting the relationships and namespaces
+// whose deletions they recorded.
+func (mdb *memdbDatastore) deleteChangelogBeforeLocalCallerMustLock(oldest revisions.TimestampRevision) (common.DeletionCounts, error) {
+	removed := common.DeletionCounts{}
+
+	tx := mdb.db.Txn(true)
+	defer tx.Abort()
+
+	it, err := tx.ReverseLowerBound(tableChangelog, indexRevision, oldest.TimestampNanoSec()-1)
+	if err != nil {
+		return removed, err
+	}
+
+	// Entries cannot be deleted while iterating, so collect them first.
+	var expired []*changelog
+	for raw := it.Next(); raw != nil; raw = it.Next() {
+		expired = append(expired, raw.(*changelog))
+	}
+
+	for _, change := range expired {
+		if err := tx.Delete(tableChangelog, change); err != nil {
+			return removed, err
+		}
+
+		for _, update := range change.changes.RelationshipChanges {
+			if update.Operation == corev1.RelationTupleUpdate_DELETE {
+				removed.Relationships++
+			}
+		}
+		removed.Namespaces += int64(len(change.changes.DeletedNamespaces))
+	}
+
+	tx.Commit()
+	return removed, nil
+}
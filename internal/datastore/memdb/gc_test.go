@@ -0,0 +1,129 @@
+package memdb
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/authzed/spicedb/pkg/datastore"
+	corev1 "github.com/authzed/spicedb/pkg/proto/core/v1"
+	"github.com/authzed/spicedb/pkg/tuple"
+)
+
+func TestRunGC(t *testing.T) {
+	require := require.New(t)
+
+	gcWindow := 200 * time.Millisecond
+	ds, err := NewMemdbDatastore(0, 0, gcWindow)
+	require.NoError(err)
+	t.Cleanup(func() { ds.Close() })
+
+	ctx := context.Background()
+	writeRevision := func(i int) datastore.Revision {
+		rev, err := ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+			return rwt.WriteRelationships(ctx, []*corev1.RelationTupleUpdate{
+				tuple.Touch(tuple.MustParse(fmt.Sprintf("document:doc#viewer@user:user-%d", i))),
+			})
+		})
+		require.NoError(err)
+		return rev
+	}
+
+	oldRevisions := make([]datastore.Revision, 0, 10)
+	for i := 0; i < 10; i++ {
+		oldRevisions = append(oldRevisions, writeRevision(i))
+	}
+
+	time.Sleep(gcWindow + 50*time.Millisecond)
+
+	recentRevisions := make([]datastore.Revision, 0, 5)
+	for i := 10; i < 15; i++ {
+		recentRevisions = append(recentRevisions, writeRevision(i))
+	}
+
+	mdb := ds.(*memdbDatastore)
+	mdb.ResetGCCompleted()
+	require.NoError(mdb.RunGC())
+	require.True(mdb.HasGCRun())
+
+	// Only the newest snapshot from before the window is kept, alongside those within it.
+	require.Len(mdb.revisions, len(recentRevisions)+1)
+
+	changelogCount := 0
+	it, err := mdb.db.Txn(false).Get(tableChangelog, indexRevision)
+	require.NoError(err)
+	for raw := it.Next(); raw != nil; raw = it.Next() {
+		changelogCount++
+	}
+	require.Equal(len(recentRevisions), changelogCount)
+
+	for _, rev := range oldRevisions {
+		err := ds.CheckRevision(ctx, rev)
+		require.ErrorAs(err, &datastore.ErrInvalidRevision{})
+	}
+
+	for i, rev := range recentRevisions {
+		require.NoError(ds.CheckRevision(ctx, rev))
+
+		iter, err := ds.SnapshotReader(rev).QueryRelationships(ctx, datastore.RelationshipsFilter{
+			ResourceType: "document",
+		})
+		require.NoError(err)
+
+		count := 0
+		for found := iter.Next(); found != nil; found = iter.Next() {
+			count++
+		}
+		require.NoError(iter.Err())
+		iter.Close()
+
+		require.Equal(len(oldRevisions)+i+1, count)
+	}
+}
+
+func TestRunGCWaitsForActiveWriteTransaction(t *testing.T) {
+	require := require.New(t)
+
+	ds, err := NewMemdbDatastore(0, 0, 1*time.Hour)
+	require.NoError(err)
+	t.Cleanup(func() { ds.Close() })
+
+	ctx := context.Background()
+	written := make(chan struct{})
+	release := make(chan struct{})
+	writeDone := make(chan error, 1)
+	go func() {
+		_, err := ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+			if err := rwt.WriteRelationships(ctx, []*corev1.RelationTupleUpdate{
+				tuple.Touch(tuple.MustParse("document:doc#viewer@user:tom")),
+			}); err != nil {
+				return err
+			}
+
+			close(written)
+			<-release
+			return nil
+		})
+		writeDone <- err
+	}()
+	<-written
+
+	gcDone := make(chan error, 1)
+	go func() {
+		gcDone <- ds.(*memdbDatastore).RunGC()
+	}()
+
+	// Garbage collection must not run while the write transaction is active.
+	select {
+	case <-gcDone:
+		require.Fail("garbage collection completed while a write transaction was active")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	require.NoError(<-writeDone)
+	require.NoError(<-gcDone)
+}
@@ -7,6 +7,7 @@ import (
 	"math"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/authzed/spicedb/internal/datastore/common"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/hashicorp/go-memdb"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/authzed/spicedb/internal/datastore/revisions"
 	"github.com/authzed/spicedb/pkg/datastore"
@@ -59,7 +61,7 @@ func NewMemdbDatastore(
 	}
 
 	uniqueID := uuid.NewString()
-	return &memdbDatastore{
+	mdb := &memdbDatastore{
 		CommonDecoder: revisions.CommonDecoder{
 			Kind: revisions.Timestamp,
 		},
@@ -76,7 +78,18 @@ func NewMemdbDatastore(
 		watchBufferLength:       watchBufferLength,
 		watchBufferWriteTimeout: 100 * time.Millisecond,
 		uniqueID:                uniqueID,
-	}, nil
+	}
+
+	if mdb.gcEnabled() {
+		gcCtx, cancelGc := context.WithCancel(context.Background())
+		mdb.cancelGc = cancelGc
+		mdb.gcGroup, gcCtx = errgroup.WithContext(gcCtx)
+		mdb.gcGroup.Go(func() error {
+			return common.StartGarbageCollector(gcCtx, mdb, mdb.gcInterval(), gcWindow, gcTimeout)
+		})
+	}
+
+	return mdb, nil
 }
 
 type memdbDatastore struct {
@@ -87,11 +100,19 @@ type memdbDatastore struct {
 	revisions      []snapshot
 	activeWriteTxn *memdb.Txn
 
+	// activeWriteTxnDone is closed once the active write transaction, if any, has
+	// completed.
+	activeWriteTxnDone chan struct{}
+
 	negativeGCWindow        int64
 	quantizationPeriod      int64
 	watchBufferLength       uint16
 	watchBufferWriteTimeout time.Duration
 	uniqueID                string
+
+	gcGroup  *errgroup.Group
+	cancelGc context.CancelFunc
+	gcHasRun atomic.Bool
 }
 
 type snapshot struct {
@@ -166,6 +187,7 @@ func (mdb *memdbDatastore) ReadWriteTx(
 				tx = mdb.db.Txn(true)
 				tx.TrackChanges()
 				mdb.activeWriteTxn = tx
+				mdb.activeWriteTxnDone = make(chan struct{})
 			})
 
 			return tx, err
@@ -177,7 +199,7 @@ func (mdb *memdbDatastore) ReadWriteTx(
 			mdb.Lock()
 			if tx != nil {
 				tx.Abort()
-				mdb.activeWriteTxn = nil
+				mdb.endWriteTxnLocalCallerMustLock()
 			}
 
 			// If the error was a serialization error, retry the transaction
@@ -270,8 +292,8 @@ func (mdb *memdbDatastore) ReadWriteTx(
 			}
 
 			tx.Commit()
+			mdb.endWriteTxnLocalCallerMustLock()
 		}
-		mdb.activeWriteTxn = nil
 
 		// Create a snapshot and add it to the revisions slice
 		if mdb.db == nil {
@@ -280,12 +302,23 @@ func (mdb *memdbDatastore) ReadWriteTx(
 
 		snap := mdb.db.Snapshot()
 		mdb.revisions = append(mdb.revisions, snapshot{newRevision, snap})
+
 		return newRevision, nil
 	}
 
 	return datastore.NoRevision, NewSerializationMaxRetriesReachedErr(errors.New("serialization max retries exceeded; please reduce your parallel writes"))
 }
 
+// endWriteTxnLocalCallerMustLock clears the active write transaction and wakes
+// anything waiting for it to complete.
+func (mdb *memdbDatastore) endWriteTxnLocalCallerMustLock() {
+	mdb.activeWriteTxn = nil
+	if mdb.activeWriteTxnDone != nil {
+		close(mdb.activeWriteTxnDone)
+		mdb.activeWriteTxnDone = nil
+	}
+}
+
 func (mdb *memdbDatastore) ReadyState(_ context.Context) (datastore.ReadyState, error) {
 	mdb.RLock()
 	defer mdb.RUnlock()
@@ -301,6 +334,13 @@ func (mdb *memdbDatastore) Features(_ context.Context) (*datastore.Features, err
 }
 
 func (mdb *memdbDatastore) Close() error {
+	// Stop the garbage collector before taking the lock, as it acquires the lock
+	// itself while running.
+	if mdb.gcGroup != nil {
+		mdb.cancelGc()
+		_ = mdb.gcGroup.Wait()
+	}
+
 	mdb.Lock()
 	defer mdb.Unlock()
 
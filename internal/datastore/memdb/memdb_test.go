@@ -113,3 +113,55 @@ func TestConcurrentWriteRelsError(t *testing.T) {
 	require.Error(werr)
 	require.ErrorContains(werr, "serialization max retries exceeded")
 }
+
+func TestQueryRelationshipsStopsOnContextCancelation(t *testing.T) {
+	require := require.New(t)
+
+	ds, err := NewMemdbDatastore(0, 0, DisableGC)
+	require.NoError(err)
+
+	updates := []*corev1.RelationTupleUpdate{}
+	for i := 0; i < 10; i++ {
+		updates = append(updates, &corev1.RelationTupleUpdate{
+			Operation: corev1.RelationTupleUpdate_TOUCH,
+			Tuple:     tuple.MustParse(fmt.Sprintf("document:doc-%d#viewer@user:tom", i)),
+		})
+	}
+
+	revision, err := ds.ReadWriteTx(context.Background(), func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+		return rwt.WriteRelationships(ctx, updates)
+	})
+	require.NoError(err)
+
+	for _, sort := range []options.SortOrder{options.Unsorted, options.ByResource} {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		iter, err := ds.SnapshotReader(revision).QueryRelationships(ctx, datastore.RelationshipsFilter{
+			ResourceType: "document",
+		}, options.WithSort(sort))
+		require.NoError(err)
+
+		require.NotNil(iter.Next())
+		cancel()
+
+		require.Nil(iter.Next())
+		require.ErrorIs(iter.Err(), context.Canceled)
+		iter.Close()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = ds.SnapshotReader(revision).QueryRelationships(ctx, datastore.RelationshipsFilter{
+		ResourceType: "document",
+	}, options.WithSort(options.BySubject))
+	require.ErrorIs(err, context.Canceled)
+
+	iter, err := ds.SnapshotReader(revision).ReverseQueryRelationships(ctx, datastore.SubjectsFilter{
+		SubjectType: "user",
+	})
+	require.NoError(err)
+	require.Nil(iter.Next())
+	require.ErrorIs(iter.Err(), context.Canceled)
+	iter.Close()
+}
@@ -27,7 +27,7 @@ type memdbReader struct {
 
 // QueryRelationships reads relationships starting from the resource side.
 func (r *memdbReader) QueryRelationships(
-	_ context.Context,
+	ctx context.Context,
 	filter datastore.RelationshipsFilter,
 	opts ...options.QueryOptionsOption,
 ) (datastore.RelationshipIterator, error) {
@@ -69,11 +69,11 @@ func (r *memdbReader) QueryRelationships(
 		fallthrough
 
 	case options.ByResource:
-		iter := newMemdbTupleIterator(filteredIterator, queryOpts.Limit, queryOpts.Sort)
+		iter := newMemdbTupleIterator(ctx, filteredIterator, queryOpts.Limit, queryOpts.Sort)
 		return iter, nil
 
 	case options.BySubject:
-		return newSubjectSortedIterator(filteredIterator, queryOpts.Limit)
+		return newSubjectSortedIterator(ctx, filteredIterator, queryOpts.Limit)
 
 	default:
 		return nil, spiceerrors.MustBugf("unsupported sort order: %v", queryOpts.Sort)
@@ -88,7 +88,7 @@ func mustHaveBeenClosed(iter *memdbTupleIterator) {
 
 // ReverseQueryRelationships reads relationships starting from the subject.
 func (r *memdbReader) ReverseQueryRelationships(
-	_ context.Context,
+	ctx context.Context,
 	subjectsFilter datastore.SubjectsFilter,
 	opts ...options.ReverseQueryOptionsOption,
 ) (datastore.RelationshipIterator, error) {
@@ -131,7 +131,7 @@ func (r *memdbReader) ReverseQueryRelationships(
 	)
 	filteredIterator := memdb.NewFilterIterator(iterator, matchingRelationshipsFilterFunc)
 
-	return newMemdbTupleIterator(filteredIterator, queryOpts.LimitForReverse, queryOpts.SortForReverse), nil
+	return newMemdbTupleIterator(ctx, filteredIterator, queryOpts.LimitForReverse, queryOpts.SortForReverse), nil
 }
 
 // ReadNamespace reads a namespace definition and version and returns it, and the revision at
@@ -362,11 +362,15 @@ func makeCursorFilterFn(after *core.RelationTuple, order options.SortOrder) func
 	return noopCursorFilter
 }
 
-func newSubjectSortedIterator(it memdb.ResultIterator, limit *uint64) (datastore.RelationshipIterator, error) {
+func newSubjectSortedIterator(ctx context.Context, it memdb.ResultIterator, limit *uint64) (datastore.RelationshipIterator, error) {
 	results := make([]*core.RelationTuple, 0)
 
 	// Coalesce all of the results into memory
 	for foundRaw := it.Next(); foundRaw != nil; foundRaw = it.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		rt, err := foundRaw.(*relationship).RelationTuple()
 		if err != nil {
 			return nil, err
@@ -408,13 +412,14 @@ func eq(lhsNamespace, lhsObjectID, lhsRelation string, rhs *core.ObjectAndRelati
 	return lhsNamespace == rhs.Namespace && lhsObjectID == rhs.ObjectId && lhsRelation == rhs.Relation
 }
 
-func newMemdbTupleIterator(it memdb.ResultIterator, limit *uint64, order options.SortOrder) *memdbTupleIterator {
-	iter := &memdbTupleIterator{it: it, limit: limit, order: order}
+func newMemdbTupleIterator(ctx context.Context, it memdb.ResultIterator, limit *uint64, order options.SortOrder) *memdbTupleIterator {
+	iter := &memdbTupleIterator{ctx: ctx, it: it, limit: limit, order: order}
 	runtime.SetFinalizer(iter, mustHaveBeenClosed)
 	return iter
 }
 
 type memdbTupleIterator struct {
+	ctx    context.Context
 	closed bool
 	it     memdb.ResultIterator
 	limit  *uint64
@@ -429,6 +434,13 @@ func (mti *memdbTupleIterator) Next() *core.RelationTuple {
 		return nil
 	}
 
+	// Stop iterating once the query's context is done, e.g. because the
+	// client of the request has gone away.
+	if err := mti.ctx.Err(); err != nil {
+		mti.err = err
+		return nil
+	}
+
 	foundRaw := mti.it.Next()
 	if foundRaw == nil {
 		return nil
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/authzed/spicedb/pkg/datastore"
 )
 
 func TestHeadRevision(t *testing.T) {
@@ -26,6 +28,34 @@ func TestHeadRevision(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestOptimizedRevisionIsQuantized(t *testing.T) {
+	// With a quantization period of a century, the current quantization window spans from the
+	// Unix epoch through 2070, so both calls below always fall within the same window.
+	ds, err := NewMemdbDatastore(0, 100*365*24*time.Hour, DisableGC)
+	require.NoError(t, err)
+	t.Cleanup(func() { ds.Close() })
+
+	ctx := context.Background()
+	first, err := ds.OptimizedRevision(ctx)
+	require.NoError(t, err)
+
+	_, err = ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+		return nil
+	})
+	require.NoError(t, err)
+
+	// Within the quantization window, the same revision is returned even though
+	// the head revision has moved forward.
+	second, err := ds.OptimizedRevision(ctx)
+	require.NoError(t, err)
+	require.True(t, first.Equal(second), "expected %s to equal %s", first, second)
+
+	head, err := ds.HeadRevision(ctx)
+	require.NoError(t, err)
+	require.True(t, second.LessThan(head))
+	require.NoError(t, ds.CheckRevision(ctx, second))
+}
+
 func (mdb *memdbDatastore) ExampleRetryableError() error {
 	return errSerialization
 }
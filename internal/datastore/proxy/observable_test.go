@@ -0,0 +1,43 @@
+package proxy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	promclient "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authzed/spicedb/internal/datastore/proxy/proxy_test"
+	"github.com/authzed/spicedb/internal/datastore/revisions"
+)
+
+func TestObservableProxyRecordsQueryLatency(t *testing.T) {
+	require := require.New(t)
+
+	const simulatedLatency = 50 * time.Millisecond
+
+	delegate := &proxy_test.MockDatastore{}
+	delegate.On("HeadRevision").After(simulatedLatency).Return(revisions.NewForTransactionID(1), nil).Once()
+
+	readHistogram := func() *promclient.Histogram {
+		var metric promclient.Metric
+		require.NoError(queryLatency.WithLabelValues("HeadRevision").(prometheus.Metric).Write(&metric))
+		return metric.GetHistogram()
+	}
+
+	before := readHistogram()
+
+	ds := NewObservableDatastoreProxy(delegate)
+	_, err := ds.HeadRevision(context.Background())
+	require.NoError(err)
+	delegate.AssertExpectations(t)
+
+	after := readHistogram()
+	require.Equal(before.GetSampleCount()+1, after.GetSampleCount())
+
+	observed := time.Duration((after.GetSampleSum() - before.GetSampleSum()) * float64(time.Second))
+	require.GreaterOrEqual(observed, simulatedLatency)
+	require.Less(observed, 10*simulatedLatency)
+}
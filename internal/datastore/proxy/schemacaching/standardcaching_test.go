@@ -403,6 +403,55 @@ func TestSnapshotCachingRealDatastore(t *testing.T) {
 	}
 }
 
+func TestSnapshotCachingAcrossSchemaChanges(t *testing.T) {
+	require := require.New(t)
+
+	rawDS, err := memdb.NewMemdbDatastore(0, 0, memdb.DisableGC)
+	require.NoError(err)
+
+	ctx := context.Background()
+	ds := NewCachingDatastoreProxy(rawDS, DatastoreProxyTestCache(t), 1*time.Hour, JustInTimeCaching, 100*time.Millisecond)
+
+	original := ns.Namespace("document", ns.MustRelation("viewer", nil, ns.AllowedRelation("user", "...")))
+	writtenRev, err := ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+		return rwt.WriteNamespaces(ctx, original)
+	})
+	require.NoError(err)
+
+	// Load the namespace into the cache.
+	found, _, err := ds.SnapshotReader(writtenRev).ReadNamespaceByName(ctx, "document")
+	require.NoError(err)
+	testutil.RequireProtoEqual(t, original, found, "found different namespaces")
+
+	// An update must be visible immediately at the revision at which it was written.
+	updated := ns.Namespace("document",
+		ns.MustRelation("viewer", nil, ns.AllowedRelation("user", "...")),
+		ns.MustRelation("editor", nil, ns.AllowedRelation("user", "...")),
+	)
+	updatedRev, err := ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+		return rwt.WriteNamespaces(ctx, updated)
+	})
+	require.NoError(err)
+
+	found, _, err = ds.SnapshotReader(updatedRev).ReadNamespaceByName(ctx, "document")
+	require.NoError(err)
+	testutil.RequireProtoEqual(t, updated, found, "found different namespaces")
+
+	// Once deleted, reads at newer revisions must report the namespace as missing,
+	// while reads at the older revision are still served.
+	deletedRev, err := ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+		return rwt.DeleteNamespaces(ctx, "document")
+	})
+	require.NoError(err)
+
+	_, _, err = ds.SnapshotReader(deletedRev).ReadNamespaceByName(ctx, "document")
+	require.ErrorAs(err, &datastore.ErrNamespaceNotFound{})
+
+	found, _, err = ds.SnapshotReader(writtenRev).ReadNamespaceByName(ctx, "document")
+	require.NoError(err)
+	testutil.RequireProtoEqual(t, original, found, "found different namespaces")
+}
+
 type reader struct {
 	proxy_test.MockReader
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/authzed/spicedb/internal/caveats"
 	"github.com/authzed/spicedb/internal/dispatch"
 	core "github.com/authzed/spicedb/pkg/proto/core/v1"
 	v1 "github.com/authzed/spicedb/pkg/proto/dispatch/v1"
@@ -146,6 +147,116 @@ func TestMaxDepthCaching(t *testing.T) {
 	}
 }
 
+func TestCachedCaveatedCheckRetainsExpression(t *testing.T) {
+	require := require.New(t)
+
+	parsed := tuple.ParseONR("document:doc1#read")
+	req := &v1.DispatchCheckRequest{
+		ResourceRelation: RR(parsed.Namespace, parsed.Relation),
+		ResourceIds:      []string{parsed.ObjectId},
+		Subject:          tuple.ParseSubjectONR("user:user1#..."),
+		Metadata: &v1.ResolverMeta{
+			AtRevision:     decimal.Zero.String(),
+			DepthRemaining: 50,
+		},
+	}
+
+	// The delegate must only be invoked once; the second check is served from
+	// the cache. Caveat context is applied above the dispatcher, so the cached
+	// result must carry the unevaluated expression rather than a decision.
+	delegate := delegateDispatchMock{&mock.Mock{}}
+	delegate.On("DispatchCheck", req).Return(&v1.DispatchCheckResponse{
+		ResultsByResourceId: map[string]*v1.ResourceCheckResult{
+			parsed.ObjectId: {
+				Membership: v1.ResourceCheckResult_CAVEATED_MEMBER,
+				Expression: caveats.CaveatExprForTesting("somecaveat"),
+			},
+		},
+		Metadata: &v1.ResponseMeta{
+			DispatchCount: 1,
+			DepthRequired: 1,
+		},
+	}, nil).Times(1)
+
+	dispatch, err := NewCachingDispatcher(DispatchTestCache(t), false, "", nil)
+	require.NoError(err)
+	dispatch.SetDelegate(delegate)
+	defer dispatch.Close()
+
+	for i := 0; i < 2; i++ {
+		resp, err := dispatch.DispatchCheck(context.Background(), req)
+		require.NoError(err)
+
+		result := resp.ResultsByResourceId[parsed.ObjectId]
+		require.Equal(v1.ResourceCheckResult_CAVEATED_MEMBER, result.Membership)
+		require.Equal("somecaveat", result.Expression.GetCaveat().CaveatName)
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	delegate.AssertExpectations(t)
+}
+
+func TestCachedNegativeCheckIsScopedToRevision(t *testing.T) {
+	require := require.New(t)
+
+	parsed := tuple.ParseONR("document:doc1#read")
+	reqAtRevision := func(revision int64) *v1.DispatchCheckRequest {
+		return &v1.DispatchCheckRequest{
+			ResourceRelation: RR(parsed.Namespace, parsed.Relation),
+			ResourceIds:      []string{parsed.ObjectId},
+			Subject:          tuple.ParseSubjectONR("user:user1#..."),
+			Metadata: &v1.ResolverMeta{
+				AtRevision:     decimal.NewFromInt(revision).String(),
+				DepthRemaining: 50,
+			},
+		}
+	}
+
+	// The subject is not a member at the first revision, and is granted access at the
+	// second revision.
+	delegate := delegateDispatchMock{&mock.Mock{}}
+	delegate.On("DispatchCheck", reqAtRevision(1)).Return(&v1.DispatchCheckResponse{
+		ResultsByResourceId: map[string]*v1.ResourceCheckResult{},
+		Metadata: &v1.ResponseMeta{
+			DispatchCount: 1,
+			DepthRequired: 1,
+		},
+	}, nil).Times(1)
+	delegate.On("DispatchCheck", reqAtRevision(2)).Return(&v1.DispatchCheckResponse{
+		ResultsByResourceId: map[string]*v1.ResourceCheckResult{
+			parsed.ObjectId: {
+				Membership: v1.ResourceCheckResult_MEMBER,
+			},
+		},
+		Metadata: &v1.ResponseMeta{
+			DispatchCount: 1,
+			DepthRequired: 1,
+		},
+	}, nil).Times(1)
+
+	dispatch, err := NewCachingDispatcher(DispatchTestCache(t), false, "", nil)
+	require.NoError(err)
+	dispatch.SetDelegate(delegate)
+	defer dispatch.Close()
+
+	// The negative result is cached for the first revision.
+	for i := 0; i < 2; i++ {
+		resp, err := dispatch.DispatchCheck(context.Background(), reqAtRevision(1))
+		require.NoError(err)
+		require.Empty(resp.ResultsByResourceId)
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// The cached negative result must not mask the grant at the later revision.
+	resp, err := dispatch.DispatchCheck(context.Background(), reqAtRevision(2))
+	require.NoError(err)
+	require.Equal(v1.ResourceCheckResult_MEMBER, resp.ResultsByResourceId[parsed.ObjectId].Membership)
+
+	delegate.AssertExpectations(t)
+}
+
 type delegateDispatchMock struct {
 	*mock.Mock
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/authzed/grpcutil"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
@@ -36,6 +37,7 @@ type optionState struct {
 	remoteDispatchTimeout  time.Duration
 	secondaryUpstreamAddrs map[string]string
 	secondaryUpstreamExprs map[string]string
+	secondaryUpstreamDelay time.Duration
 }
 
 // MetricsEnabled enables issuing prometheus metrics
@@ -84,6 +86,14 @@ func SecondaryUpstreamExprs(addrs map[string]string) Option {
 	}
 }
 
+// SecondaryUpstreamDelay sets the duration to wait for the primary dispatch to
+// succeed before issuing any secondary dispatches.
+func SecondaryUpstreamDelay(delay time.Duration) Option {
+	return func(state *optionState) {
+		state.secondaryUpstreamDelay = delay
+	}
+}
+
 // GrpcPresharedKey sets the preshared key used to authenticate for optional
 // cluster dispatching.
 func GrpcPresharedKey(key string) Option {
@@ -159,6 +169,13 @@ func NewDispatcher(options ...Option) (dispatch.Dispatcher, error) {
 
 		opts.grpcDialOpts = append(opts.grpcDialOpts, grpc.WithDefaultCallOptions(grpc.UseCompressor("s2")))
 
+		// Propagate the trace context on every dispatch call, including the streaming
+		// ones, so that the spans on the dispatched-to node join the caller's trace.
+		opts.grpcDialOpts = append(opts.grpcDialOpts,
+			grpc.WithChainUnaryInterceptor(otelgrpc.UnaryClientInterceptor()),   // nolint: staticcheck
+			grpc.WithChainStreamInterceptor(otelgrpc.StreamClientInterceptor()), // nolint: staticcheck
+		)
+
 		conn, err := grpc.Dial(opts.upstreamAddr, opts.grpcDialOpts...)
 		if err != nil {
 			return nil, err
@@ -188,6 +205,7 @@ func NewDispatcher(options ...Option) (dispatch.Dispatcher, error) {
 		redispatch = remote.NewClusterDispatcher(v1.NewDispatchServiceClient(conn), conn, remote.ClusterDispatcherConfig{
 			KeyHandler:             &keys.CanonicalKeyHandler{},
 			DispatchOverallTimeout: opts.remoteDispatchTimeout,
+			SecondaryDispatchDelay: opts.secondaryUpstreamDelay,
 		}, secondaryClients, secondaryExprs)
 		redispatch = singleflight.New(redispatch, &keys.CanonicalKeyHandler{})
 	}
@@ -4,9 +4,16 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.uber.org/goleak"
 
 	"github.com/authzed/spicedb/internal/datastore/common"
@@ -186,6 +193,318 @@ func TestMaxDepth(t *testing.T) {
 	require.Error(err)
 }
 
+func TestMaxDepthWithRecursiveSchema(t *testing.T) {
+	defer goleak.VerifyNone(t, goleakIgnores...)
+
+	schema := `
+		definition user {}
+
+		definition folder {
+			relation parent: folder
+			relation viewer: user
+			permission view = viewer + parent->view
+		}
+	`
+
+	testCases := []struct {
+		name string
+		rels []*core.RelationTuple
+	}{
+		{
+			"self-referential",
+			[]*core.RelationTuple{
+				tuple.MustParse("folder:oops#parent@folder:oops"),
+			},
+		},
+		{
+			"cycle",
+			[]*core.RelationTuple{
+				tuple.MustParse("folder:first#parent@folder:second"),
+				tuple.MustParse("folder:second#parent@folder:first"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			require := require.New(t)
+
+			ctx, dispatcher, revision := newLocalDispatcherWithSchemaAndRels(t, schema, tc.rels)
+			defer dispatcher.Close()
+
+			resourceID := tc.rels[0].ResourceAndRelation.ObjectId
+			_, err := dispatcher.DispatchCheck(ctx, &v1.DispatchCheckRequest{
+				ResourceRelation: RR("folder", "view"),
+				ResourceIds:      []string{resourceID},
+				ResultsSetting:   v1.DispatchCheckRequest_ALLOW_SINGLE_RESULT,
+				Subject:          ONR("user", "fake", graph.Ellipsis),
+				Metadata: &v1.ResolverMeta{
+					AtRevision:     revision.String(),
+					DepthRemaining: 50,
+				},
+			})
+
+			var maxDepthErr dispatch.MaxDepthExceededError
+			require.ErrorAs(err, &maxDepthErr)
+		})
+	}
+}
+
+func TestCheckWithCyclicGroupMembership(t *testing.T) {
+	defer goleak.VerifyNone(t, goleakIgnores...)
+
+	schema := `
+		definition user {}
+
+		definition group {
+			relation member: user | group#member
+		}
+	`
+
+	rels := []*core.RelationTuple{
+		tuple.MustParse("group:first#member@group:second#member"),
+		tuple.MustParse("group:second#member@group:first#member"),
+		tuple.MustParse("group:second#member@user:tom"),
+	}
+
+	testCases := []struct {
+		name           string
+		subject        *core.ObjectAndRelation
+		expectedMember bool
+	}{
+		{"member reachable through the cycle", ONR("user", "tom", graph.Ellipsis), true},
+		{"member not found anywhere in the cycle", ONR("user", "fake", graph.Ellipsis), false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			require := require.New(t)
+
+			ctx, dispatcher, revision := newLocalDispatcherWithSchemaAndRels(t, schema, rels)
+			defer dispatcher.Close()
+
+			resp, err := dispatcher.DispatchCheck(ctx, &v1.DispatchCheckRequest{
+				ResourceRelation: RR("group", "member"),
+				ResourceIds:      []string{"first"},
+				ResultsSetting:   v1.DispatchCheckRequest_ALLOW_SINGLE_RESULT,
+				Subject:          tc.subject,
+				Metadata: &v1.ResolverMeta{
+					AtRevision:     revision.String(),
+					DepthRemaining: 50,
+				},
+			})
+
+			if tc.expectedMember {
+				require.NoError(err)
+				require.Equal(v1.ResourceCheckResult_MEMBER, resp.ResultsByResourceId["first"].Membership)
+				return
+			}
+
+			// A subject which is never found keeps walking the cycle until the depth
+			// limit is reached, which bounds the work rather than hanging.
+			var maxDepthErr dispatch.MaxDepthExceededError
+			require.ErrorAs(err, &maxDepthErr)
+		})
+	}
+}
+
+func TestDeepAcyclicHierarchyResolves(t *testing.T) {
+	defer goleak.VerifyNone(t, goleakIgnores...)
+
+	require := require.New(t)
+
+	schema := `
+		definition user {}
+
+		definition folder {
+			relation parent: folder
+			relation viewer: user
+			permission view = viewer + parent->view
+		}
+	`
+
+	// Build a chain of folders, each the parent of the next, with the viewer
+	// only granted on the root. Unlike a cycle, this must resolve within the
+	// depth limit.
+	const chainLength = 20
+	rels := []*core.RelationTuple{
+		tuple.MustParse("folder:f0#viewer@user:tom"),
+	}
+	for i := 1; i < chainLength; i++ {
+		rels = append(rels, tuple.MustParse(fmt.Sprintf("folder:f%d#parent@folder:f%d", i, i-1)))
+	}
+
+	ctx, dispatcher, revision := newLocalDispatcherWithSchemaAndRels(t, schema, rels)
+	defer dispatcher.Close()
+
+	for _, subject := range []struct {
+		userID   string
+		isMember bool
+	}{
+		{"tom", true},
+		{"sarah", false},
+	} {
+		resourceID := fmt.Sprintf("f%d", chainLength-1)
+		checkResult, err := dispatcher.DispatchCheck(ctx, &v1.DispatchCheckRequest{
+			ResourceRelation: RR("folder", "view"),
+			ResourceIds:      []string{resourceID},
+			ResultsSetting:   v1.DispatchCheckRequest_ALLOW_SINGLE_RESULT,
+			Subject:          ONR("user", subject.userID, graph.Ellipsis),
+			Metadata: &v1.ResolverMeta{
+				AtRevision:     revision.String(),
+				DepthRemaining: 50,
+			},
+		})
+		require.NoError(err)
+
+		isMember := false
+		if found, ok := checkResult.ResultsByResourceId[resourceID]; ok {
+			isMember = found.Membership == v1.ResourceCheckResult_MEMBER
+		}
+		require.Equal(subject.isMember, isMember, "unexpected membership for user %s", subject.userID)
+	}
+}
+
+func TestCheckPermissionReferencingPermissions(t *testing.T) {
+	defer goleak.VerifyNone(t, goleakIgnores...)
+
+	// view is built on edit, which is itself built on manage, so resolving view for an
+	// owner must go through both intermediate permissions.
+	schema := `
+		definition user {}
+
+		definition document {
+			relation owner: user
+			relation editor: user
+			relation viewer: user
+			permission manage = owner
+			permission edit = manage + editor
+			permission view = edit + viewer
+		}
+	`
+
+	rels := []*core.RelationTuple{
+		tuple.MustParse("document:doc1#owner@user:owner"),
+		tuple.MustParse("document:doc1#editor@user:editor"),
+		tuple.MustParse("document:doc1#viewer@user:viewer"),
+	}
+
+	testCases := []struct {
+		permission      string
+		userID          string
+		expectPermitted bool
+	}{
+		{"view", "owner", true},
+		{"view", "editor", true},
+		{"view", "viewer", true},
+		{"view", "stranger", false},
+		{"edit", "owner", true},
+		{"edit", "editor", true},
+		{"edit", "viewer", false},
+		{"manage", "owner", true},
+		{"manage", "editor", false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprintf("%s@%s", tc.permission, tc.userID), func(t *testing.T) {
+			require := require.New(t)
+
+			ctx, dispatcher, revision := newLocalDispatcherWithSchemaAndRels(t, schema, rels)
+			defer dispatcher.Close()
+
+			resp, err := dispatcher.DispatchCheck(ctx, &v1.DispatchCheckRequest{
+				ResourceRelation: RR("document", tc.permission),
+				ResourceIds:      []string{"doc1"},
+				ResultsSetting:   v1.DispatchCheckRequest_ALLOW_SINGLE_RESULT,
+				Subject:          ONR("user", tc.userID, graph.Ellipsis),
+				Metadata: &v1.ResolverMeta{
+					AtRevision:     revision.String(),
+					DepthRemaining: 50,
+				},
+			})
+			require.NoError(err)
+
+			isPermitted := false
+			if found, ok := resp.ResultsByResourceId["doc1"]; ok {
+				isPermitted = found.Membership == v1.ResourceCheckResult_MEMBER
+			}
+			require.Equal(tc.expectPermitted, isPermitted)
+		})
+	}
+}
+
+func TestCheckWithWildcards(t *testing.T) {
+	defer goleak.VerifyNone(t, goleakIgnores...)
+
+	schema := `
+		definition user {}
+
+		definition folder {
+			relation viewer: user | user:*
+			permission view = viewer
+		}
+
+		definition document {
+			relation parent: folder
+			relation viewer: user | user:*
+			relation banned: user
+			permission view = (viewer + parent->view) - banned
+		}
+	`
+
+	rels := []*core.RelationTuple{
+		tuple.MustParse("document:public#viewer@user:*"),
+		tuple.MustParse("document:public#banned@user:villain"),
+		tuple.MustParse("folder:shared#viewer@user:*"),
+		tuple.MustParse("document:infolder#parent@folder:shared"),
+		tuple.MustParse("document:private#viewer@user:tom"),
+	}
+
+	testCases := []struct {
+		resourceID string
+		userID     string
+		isMember   bool
+	}{
+		{"public", "tom", true},
+		{"public", "anyone", true},
+		{"public", "villain", false},
+		{"infolder", "anyone", true},
+		{"private", "tom", true},
+		{"private", "anyone", false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprintf("document:%s#view@user:%s=>%t", tc.resourceID, tc.userID, tc.isMember), func(t *testing.T) {
+			require := require.New(t)
+
+			ctx, dispatcher, revision := newLocalDispatcherWithSchemaAndRels(t, schema, rels)
+			defer dispatcher.Close()
+
+			checkResult, err := dispatcher.DispatchCheck(ctx, &v1.DispatchCheckRequest{
+				ResourceRelation: RR("document", "view"),
+				ResourceIds:      []string{tc.resourceID},
+				ResultsSetting:   v1.DispatchCheckRequest_ALLOW_SINGLE_RESULT,
+				Subject:          ONR("user", tc.userID, graph.Ellipsis),
+				Metadata: &v1.ResolverMeta{
+					AtRevision:     revision.String(),
+					DepthRemaining: 50,
+				},
+			})
+			require.NoError(err)
+
+			isMember := false
+			if found, ok := checkResult.ResultsByResourceId[tc.resourceID]; ok {
+				isMember = found.Membership == v1.ResourceCheckResult_MEMBER
+			}
+			require.Equal(tc.isMember, isMember)
+		})
+	}
+}
+
 func TestCheckMetadata(t *testing.T) {
 	type expected struct {
 		relation              string
@@ -432,6 +751,205 @@ func newLocalDispatcher(t testing.TB) (context.Context, dispatch.Dispatcher, dat
 	return newLocalDispatcherWithConcurrencyLimit(t, 10)
 }
 
+var (
+	spanrecorder        = tracetest.NewSpanRecorder()
+	installSpanRecorder sync.Once
+)
+
+func TestCheckDispatchSpans(t *testing.T) {
+	// The package tracer binds to the first provider installed globally, so the
+	// recorder is installed once and spans are filtered by trace below.
+	installSpanRecorder.Do(func() {
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanrecorder)))
+	})
+
+	ctx, dispatcher, revision := newLocalDispatcherWithSchemaAndRels(t, `
+		definition user {}
+
+		definition folder {
+			relation viewer: user
+			permission view = viewer
+		}
+
+		definition document {
+			relation parent: folder
+			relation viewer: user
+			permission view = viewer + parent->view
+		}
+	`, []*core.RelationTuple{
+		tuple.MustParse("document:doc1#parent@folder:folder1"),
+		tuple.MustParse("folder:folder1#viewer@user:tom"),
+	})
+
+	ctx, rootSpan := otel.Tracer("test").Start(ctx, "CheckPermission")
+	resp, err := dispatcher.DispatchCheck(ctx, &v1.DispatchCheckRequest{
+		ResourceRelation: RR("document", "view"),
+		ResourceIds:      []string{"doc1"},
+		Subject:          ONR("user", "tom", graph.Ellipsis),
+		ResultsSetting:   v1.DispatchCheckRequest_ALLOW_SINGLE_RESULT,
+		Metadata: &v1.ResolverMeta{
+			AtRevision:     revision.String(),
+			DepthRemaining: 50,
+		},
+	})
+	rootSpan.End()
+	require.NoError(t, err)
+	require.Equal(t, v1.ResourceCheckResult_MEMBER, resp.ResultsByResourceId["doc1"].Membership)
+
+	spansByName := map[string]sdktrace.ReadOnlySpan{}
+	spansByID := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range spanrecorder.Ended() {
+		if span.SpanContext().TraceID() != rootSpan.SpanContext().TraceID() {
+			continue
+		}
+		spansByName[span.Name()] = span
+		spansByID[span.SpanContext().SpanID().String()] = span
+	}
+
+	hasAncestor := func(span sdktrace.ReadOnlySpan, ancestor sdktrace.ReadOnlySpan) bool {
+		for span.Parent().IsValid() {
+			parent, ok := spansByID[span.Parent().SpanID().String()]
+			if !ok {
+				return false
+			}
+			if parent.SpanContext().SpanID() == ancestor.SpanContext().SpanID() {
+				return true
+			}
+			span = parent
+		}
+		return false
+	}
+
+	checkSpan, ok := spansByName["DispatchCheck → document#view@user#..."]
+	require.True(t, ok)
+	require.Equal(t, rootSpan.SpanContext().SpanID(), checkSpan.Parent().SpanID())
+	require.Contains(t, checkSpan.Attributes(), attribute.String("resource-type", "document#view"))
+	require.Contains(t, checkSpan.Attributes(), attribute.String("subject", "user:tom"))
+	require.Contains(t, checkSpan.Attributes(), attribute.String("revision", revision.String()))
+
+	// The dispatch through the arrow must be nested under the top-level check.
+	folderSpan, ok := spansByName["DispatchCheck → folder#view@user#..."]
+	require.True(t, ok)
+	require.True(t, hasAncestor(folderSpan, checkSpan))
+	require.Contains(t, folderSpan.Attributes(), attribute.String("resource-type", "folder#view"))
+
+	// The caching dispatcher records on the caller's span whether the result was cached.
+	require.Contains(t, spansByName["CheckPermission"].Attributes(), attribute.Bool("cached", false))
+}
+
+// concurrencyTrackingDispatcher is a dispatcher which records the maximum number of
+// concurrent check dispatches it receives, and otherwise reports no membership.
+type concurrencyTrackingDispatcher struct {
+	dispatch.Dispatcher
+
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+	total       atomic.Int32
+}
+
+func (ctd *concurrencyTrackingDispatcher) DispatchCheck(ctx context.Context, _ *v1.DispatchCheckRequest) (*v1.DispatchCheckResponse, error) {
+	ctd.total.Add(1)
+	current := ctd.inFlight.Add(1)
+	defer ctd.inFlight.Add(-1)
+
+	for {
+		seen := ctd.maxInFlight.Load()
+		if current <= seen || ctd.maxInFlight.CompareAndSwap(seen, current) {
+			break
+		}
+	}
+
+	select {
+	case <-time.After(5 * time.Millisecond):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	return &v1.DispatchCheckResponse{
+		Metadata: &v1.ResponseMeta{DispatchCount: 1},
+	}, nil
+}
+
+func TestCheckConcurrencyLimit(t *testing.T) {
+	const relationCount = 20
+
+	relations := make([]string, 0, relationCount)
+	relationDefs := make([]string, 0, relationCount)
+	for i := 0; i < relationCount; i++ {
+		relations = append(relations, fmt.Sprintf("rel%d", i))
+		relationDefs = append(relationDefs, fmt.Sprintf("relation rel%d: user", i))
+	}
+
+	schema := fmt.Sprintf(`
+		definition user {}
+
+		definition document {
+			%s
+			permission view = %s
+		}
+	`, strings.Join(relationDefs, "\n"), strings.Join(relations, " + "))
+
+	for _, limit := range []uint16{1, 3, 10} {
+		limit := limit
+		t.Run(fmt.Sprintf("limit %d", limit), func(t *testing.T) {
+			ctx, _, revision := newLocalDispatcherWithSchemaAndRels(t, schema, nil)
+
+			tracking := &concurrencyTrackingDispatcher{}
+			dispatcher := NewDispatcher(tracking, SharedConcurrencyLimits(limit))
+
+			resp, err := dispatcher.DispatchCheck(ctx, &v1.DispatchCheckRequest{
+				ResourceRelation: RR("document", "view"),
+				ResourceIds:      []string{"doc1"},
+				Subject:          ONR("user", "tom", graph.Ellipsis),
+				ResultsSetting:   v1.DispatchCheckRequest_REQUIRE_ALL_RESULTS,
+				Metadata: &v1.ResolverMeta{
+					AtRevision:     revision.String(),
+					DepthRemaining: 50,
+				},
+			})
+			require.NoError(t, err)
+			require.Empty(t, resp.ResultsByResourceId)
+
+			// Every branch of the union is dispatched, but never more than the limit at once.
+			require.Equal(t, int32(relationCount), tracking.total.Load())
+			require.LessOrEqual(t, tracking.maxInFlight.Load(), int32(limit))
+			require.Positive(t, tracking.maxInFlight.Load())
+		})
+	}
+}
+
+func TestCheckIntersectionShortCircuits(t *testing.T) {
+	ctx, _, revision := newLocalDispatcherWithSchemaAndRels(t, `
+		definition user {}
+
+		definition document {
+			relation first: user
+			relation second: user
+			relation third: user
+			permission view = first & second & third
+		}
+	`, nil)
+
+	// With a limit of one, the branches run in order; once the first branch reports
+	// no members, the remaining branches must never be dispatched.
+	tracking := &concurrencyTrackingDispatcher{}
+	dispatcher := NewDispatcher(tracking, SharedConcurrencyLimits(1))
+
+	resp, err := dispatcher.DispatchCheck(ctx, &v1.DispatchCheckRequest{
+		ResourceRelation: RR("document", "view"),
+		ResourceIds:      []string{"doc1"},
+		Subject:          ONR("user", "tom", graph.Ellipsis),
+		ResultsSetting:   v1.DispatchCheckRequest_ALLOW_SINGLE_RESULT,
+		Metadata: &v1.ResolverMeta{
+			AtRevision:     revision.String(),
+			DepthRemaining: 50,
+		},
+	})
+	require.NoError(t, err)
+	require.Empty(t, resp.ResultsByResourceId)
+	require.Equal(t, int32(1), tracking.total.Load())
+}
+
 func newLocalDispatcherWithSchemaAndRels(t testing.TB, schema string, rels []*core.RelationTuple) (context.Context, dispatch.Dispatcher, datastore.Revision) {
 	rawDS, err := memdb.NewMemdbDatastore(0, 0, memdb.DisableGC)
 	require.NoError(t, err)
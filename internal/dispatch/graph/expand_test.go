@@ -305,6 +305,59 @@ func TestMaxDepthExpand(t *testing.T) {
 	require.Error(err)
 }
 
+func TestExpandThroughArrow(t *testing.T) {
+	defer goleak.VerifyNone(t, goleakIgnores...)
+
+	require := require.New(t)
+
+	schema := `
+		definition user {}
+
+		definition folder {
+			relation viewer: user
+		}
+
+		definition document {
+			relation parent: folder
+			permission view = parent->viewer
+		}
+	`
+
+	ctx, dispatch, revision := newLocalDispatcherWithSchemaAndRels(t, schema, []*core.RelationTuple{
+		tuple.MustParse("document:doc#parent@folder:first"),
+		tuple.MustParse("document:doc#parent@folder:second"),
+		tuple.MustParse("folder:first#viewer@user:tom"),
+		tuple.MustParse("folder:second#viewer@user:sarah"),
+	})
+
+	expandResult, err := dispatch.DispatchExpand(ctx, &v1.DispatchExpandRequest{
+		ResourceAndRelation: ONR("document", "doc", "view"),
+		Metadata: &v1.ResolverMeta{
+			AtRevision:     revision.String(),
+			DepthRemaining: 50,
+		},
+		ExpansionMode: v1.DispatchExpandRequest_SHALLOW,
+	})
+	require.NoError(err)
+
+	// The arrow is expanded into the viewer subtree of each parent folder.
+	expected := graph.Union(ONR("document", "doc", "view"),
+		graph.Union(ONR("document", "doc", "view"),
+			graph.Leaf(ONR("folder", "first", "viewer"),
+				(DS("user", "tom", "..."))),
+			graph.Leaf(ONR("folder", "second", "viewer"),
+				(DS("user", "sarah", "..."))),
+		),
+	)
+
+	if diff := cmp.Diff(expected, expandResult.TreeNode, protocmp.Transform()); diff != "" {
+		fset := token.NewFileSet()
+		err := printer.Fprint(os.Stdout, fset, serializeToFile(expandResult.TreeNode))
+		require.NoError(err)
+		t.Errorf("unexpected difference:\n%v", diff)
+	}
+}
+
 func TestCaveatedExpand(t *testing.T) {
 	defer goleak.VerifyNone(t, goleakIgnores...)
 
@@ -167,6 +167,7 @@ func (ld *localDispatcher) DispatchCheck(ctx context.Context, req *v1.DispatchCh
 		attribute.String("resource-type", resourceType),
 		attribute.StringSlice("resource-ids", req.ResourceIds),
 		attribute.String("subject", tuple.StringONR(req.Subject)),
+		attribute.String("revision", req.Metadata.GetAtRevision()),
 	))
 	defer span.End()
 
@@ -247,6 +248,7 @@ func (ld *localDispatcher) DispatchCheck(ctx context.Context, req *v1.DispatchCh
 func (ld *localDispatcher) DispatchExpand(ctx context.Context, req *v1.DispatchExpandRequest) (*v1.DispatchExpandResponse, error) {
 	ctx, span := tracer.Start(ctx, "DispatchExpand", trace.WithAttributes(
 		attribute.String("start", tuple.StringONR(req.ResourceAndRelation)),
+		attribute.String("revision", req.Metadata.GetAtRevision()),
 	))
 	defer span.End()
 
@@ -287,6 +289,7 @@ func (ld *localDispatcher) DispatchReachableResources(
 		attribute.String("resource-type", resourceType),
 		attribute.String("subject-type", subjectRelation),
 		attribute.StringSlice("subject-ids", req.SubjectIds),
+		attribute.String("revision", req.Metadata.GetAtRevision()),
 	))
 	defer span.End()
 
@@ -316,6 +319,7 @@ func (ld *localDispatcher) DispatchLookupResources(
 	ctx, span := tracer.Start(stream.Context(), "DispatchLookupResources", trace.WithAttributes(
 		attribute.String("resource-type", tuple.StringRR(req.ObjectRelation)),
 		attribute.String("subject", tuple.StringONR(req.Subject)),
+		attribute.String("revision", req.Metadata.GetAtRevision()),
 	))
 	defer span.End()
 
@@ -350,6 +354,7 @@ func (ld *localDispatcher) DispatchLookupSubjects(
 		attribute.String("resource-type", resourceType),
 		attribute.String("subject-type", subjectRelation),
 		attribute.StringSlice("resource-ids", req.ResourceIds),
+		attribute.String("revision", req.Metadata.GetAtRevision()),
 	))
 	defer span.End()
 
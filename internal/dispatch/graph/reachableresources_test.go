@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
@@ -302,6 +303,79 @@ func BenchmarkReachableResources(b *testing.B) {
 	}
 }
 
+func BenchmarkCachedReachableResources(b *testing.B) {
+	testCases := []struct {
+		start  *core.RelationReference
+		target *core.ObjectAndRelation
+	}{
+		{
+			RR("document", "view"),
+			ONR("user", "multiroleguy", "..."),
+		},
+		{
+			RR("folder", "view"),
+			ONR("user", "owner", "..."),
+		},
+	}
+
+	for _, tc := range testCases {
+		name := fmt.Sprintf(
+			"%s#%s->%s",
+			tc.start.Namespace,
+			tc.start.Relation,
+			tuple.StringONR(tc.target),
+		)
+
+		require := require.New(b)
+		rawDS, err := memdb.NewMemdbDatastore(0, 0, memdb.DisableGC)
+		require.NoError(err)
+
+		ds, revision := testfixtures.StandardDatastoreWithData(rawDS, require)
+
+		dispatcher, err := caching.NewCachingDispatcher(caching.DispatchTestCache(b), false, "", &keys.CanonicalKeyHandler{})
+		require.NoError(err)
+		dispatcher.SetDelegate(NewLocalOnlyDispatcher(10))
+
+		ctx := datastoremw.ContextWithHandle(context.Background())
+		require.NoError(datastoremw.SetInContext(ctx, ds))
+
+		req := &v1.DispatchReachableResourcesRequest{
+			ResourceRelation: tc.start,
+			SubjectRelation: &core.RelationReference{
+				Namespace: tc.target.Namespace,
+				Relation:  tc.target.Relation,
+			},
+			SubjectIds: []string{tc.target.ObjectId},
+			Metadata: &v1.ResolverMeta{
+				AtRevision:     revision.String(),
+				DepthRemaining: 50,
+			},
+		}
+
+		// Prime the cache with the first call, then give it time to converge.
+		stream := dispatch.NewCollectingDispatchStream[*v1.DispatchReachableResourcesResponse](ctx)
+		require.NoError(dispatcher.DispatchReachableResources(req, stream))
+		expectedCount := len(stream.Results())
+		require.NotZero(expectedCount)
+		time.Sleep(10 * time.Millisecond)
+
+		tc := tc
+		b.Run(name, func(t *testing.B) {
+			for n := 0; n < t.N; n++ {
+				stream := dispatch.NewCollectingDispatchStream[*v1.DispatchReachableResourcesResponse](ctx)
+				err := dispatcher.DispatchReachableResources(req, stream)
+				require.NoError(err)
+				require.Len(stream.Results(), expectedCount)
+
+				// Results served from the cache report no new dispatches.
+				for _, result := range stream.Results() {
+					require.Equal(uint32(0), result.Metadata.DispatchCount, "expected %s to be served from cache", tc.start)
+				}
+			}
+		})
+	}
+}
+
 func TestCaveatedReachableResources(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -29,8 +29,16 @@ var dispatchCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
 	Help:      "which dispatcher handled a request",
 }, []string{"request_kind", "handler_name"})
 
+var secondaryDispatchCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "spicedb",
+	Subsystem: "dispatch",
+	Name:      "remote_dispatch_secondary_total",
+	Help:      "number of secondary dispatches issued, by the secondary dispatcher used",
+}, []string{"request_kind", "handler_name"})
+
 func init() {
 	prometheus.MustRegister(dispatchCounter)
+	prometheus.MustRegister(secondaryDispatchCounter)
 }
 
 type ClusterClient interface {
@@ -48,6 +56,11 @@ type ClusterDispatcherConfig struct {
 	// DispatchOverallTimeout is the maximum duration of a dispatched request
 	// before it should timeout.
 	DispatchOverallTimeout time.Duration
+
+	// SecondaryDispatchDelay is the duration to wait for the primary dispatch
+	// to succeed before issuing any secondary dispatches. If zero, secondary
+	// dispatches are issued immediately alongside the primary dispatch.
+	SecondaryDispatchDelay time.Duration
 }
 
 // SecondaryDispatch defines a struct holding a client and its name for secondary
@@ -77,6 +90,7 @@ func NewClusterDispatcher(client ClusterClient, conn *grpc.ClientConn, config Cl
 		dispatchOverallTimeout: dispatchOverallTimeout,
 		secondaryDispatch:      secondaryDispatch,
 		secondaryDispatchExprs: secondaryDispatchExprs,
+		secondaryDispatchDelay: config.SecondaryDispatchDelay,
 	}
 }
 
@@ -87,6 +101,7 @@ type clusterDispatcher struct {
 	dispatchOverallTimeout time.Duration
 	secondaryDispatch      map[string]SecondaryDispatch
 	secondaryDispatchExprs map[string]*DispatchExpr
+	secondaryDispatchDelay time.Duration
 }
 
 func (cr *clusterDispatcher) DispatchCheck(ctx context.Context, req *v1.DispatchCheckRequest) (*v1.DispatchCheckResponse, error) {
@@ -137,6 +152,7 @@ type respTuple[S responseMessage] struct {
 type secondaryRespTuple[S responseMessage] struct {
 	handlerName string
 	resp        S
+	err         error
 }
 
 func dispatchRequest[Q requestMessage, S responseMessage](ctx context.Context, cr *clusterDispatcher, reqKey string, req Q, handler func(context.Context, ClusterClient) (S, error)) (S, error) {
@@ -163,6 +179,30 @@ func dispatchRequest[Q requestMessage, S responseMessage](ctx context.Context, c
 		primaryResultChan <- respTuple[S]{resp, err}
 	}()
 
+	// If a delay is configured, only issue the secondary dispatches if the primary has
+	// not succeeded before it elapses.
+	if cr.secondaryDispatchDelay > 0 {
+		timer := time.NewTimer(cr.secondaryDispatchDelay)
+		defer timer.Stop()
+
+		select {
+		case <-withTimeout.Done():
+			return *new(S), fmt.Errorf("check dispatch has timed out")
+
+		case r := <-primaryResultChan:
+			if r.err == nil {
+				dispatchCounter.WithLabelValues(reqKey, "(primary)").Add(1)
+				return r.resp, nil
+			}
+
+			// Return the failed result to the channel; it is only returned if none of the
+			// secondaries succeed.
+			primaryResultChan <- r
+
+		case <-timer.C:
+		}
+	}
+
 	result, err := RunDispatchExpr(expr, req)
 	if err != nil {
 		log.Warn().Err(err).Msg("error when trying to evaluate the dispatch expression")
@@ -170,6 +210,7 @@ func dispatchRequest[Q requestMessage, S responseMessage](ctx context.Context, c
 
 	log.Trace().Str("secondary-dispatchers", strings.Join(result, ",")).Object("request", req).Msg("running secondary dispatchers")
 
+	pendingSecondaries := 0
 	for _, secondaryDispatchName := range result {
 		secondary, ok := cr.secondaryDispatch[secondaryDispatchName]
 		if !ok {
@@ -178,41 +219,49 @@ func dispatchRequest[Q requestMessage, S responseMessage](ctx context.Context, c
 		}
 
 		log.Trace().Str("secondary-dispatcher", secondary.Name).Object("request", req).Msg("running secondary dispatcher")
+		secondaryDispatchCounter.WithLabelValues(reqKey, secondary.Name).Add(1)
+		pendingSecondaries++
 		go func() {
 			resp, err := handler(withTimeout, secondary.Client)
-			if err != nil {
-				// For secondary dispatches, ignore any errors, as only the primary will be handled in
-				// that scenario.
-				log.Trace().Str("secondary", secondary.Name).Err(err).Msg("got ignored secondary dispatch error")
-				return
-			}
-
-			secondaryResultChan <- secondaryRespTuple[S]{resp: resp, handlerName: secondary.Name}
+			secondaryResultChan <- secondaryRespTuple[S]{resp: resp, handlerName: secondary.Name, err: err}
 		}()
 	}
 
 	var foundError error
-	select {
-	case <-withTimeout.Done():
-		return *new(S), fmt.Errorf("check dispatch has timed out")
+	for {
+		select {
+		case <-withTimeout.Done():
+			return *new(S), fmt.Errorf("check dispatch has timed out")
 
-	case r := <-primaryResultChan:
-		if r.err == nil {
-			dispatchCounter.WithLabelValues(reqKey, "(primary)").Add(1)
-			return r.resp, nil
-		}
+		case r := <-primaryResultChan:
+			if r.err == nil {
+				dispatchCounter.WithLabelValues(reqKey, "(primary)").Add(1)
+				return r.resp, nil
+			}
 
-		// Otherwise, if an error was found, log it and we'll return after *all* the secondaries have run.
-		// This allows an otherwise error-state to be handled by one of the secondaries.
-		foundError = r.err
+			// Otherwise, if an error was found, hold onto it and only return it after *all* the
+			// secondaries have run. This allows an otherwise error-state to be handled by one of
+			// the secondaries.
+			foundError = r.err
+			primaryResultChan = nil
+
+		case r := <-secondaryResultChan:
+			pendingSecondaries--
+			if r.err == nil {
+				dispatchCounter.WithLabelValues(reqKey, r.handlerName).Add(1)
+				return r.resp, nil
+			}
 
-	case r := <-secondaryResultChan:
-		dispatchCounter.WithLabelValues(reqKey, r.handlerName).Add(1)
-		return r.resp, nil
-	}
+			// For secondary dispatches, ignore any errors, as only the primary will be handled in
+			// that scenario.
+			log.Trace().Str("secondary", r.handlerName).Err(r.err).Msg("got ignored secondary dispatch error")
+		}
 
-	dispatchCounter.WithLabelValues(reqKey, "(primary)").Add(1)
-	return *new(S), foundError
+		if primaryResultChan == nil && pendingSecondaries == 0 {
+			dispatchCounter.WithLabelValues(reqKey, "(primary)").Add(1)
+			return *new(S), foundError
+		}
+	}
 }
 
 func adjustMetadataForDispatch(metadata *v1.ResponseMeta) error {
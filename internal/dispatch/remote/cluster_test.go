@@ -12,7 +12,9 @@ import (
 	humanize "github.com/dustin/go-humanize"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 
 	"github.com/authzed/spicedb/internal/dispatch/keys"
@@ -242,6 +244,188 @@ func TestSecondaryDispatch(t *testing.T) {
 	}
 }
 
+func TestSecondaryDispatchDelay(t *testing.T) {
+	for _, tc := range []struct {
+		name                      string
+		primarySleepTime          time.Duration
+		secondaryDelay            time.Duration
+		expectedSecondaryRequests int
+	}{
+		{
+			"primary returns before the delay",
+			0 * time.Millisecond,
+			1 * time.Second,
+			0,
+		},
+		{
+			"primary is slower than the delay",
+			1 * time.Second,
+			10 * time.Millisecond,
+			1,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			conn := connectionForDispatching(t, &fakeDispatchSvc{dispatchCount: 1, sleepTime: tc.primarySleepTime})
+
+			secondarySvc := &recordingDispatchSvc{}
+			secondaryConn := connectionForDispatching(t, secondarySvc)
+
+			parsed, err := ParseDispatchExpression("check", "['secondary']")
+			require.NoError(t, err)
+
+			dispatcher := NewClusterDispatcher(v1.NewDispatchServiceClient(conn), conn, ClusterDispatcherConfig{
+				KeyHandler:             &keys.DirectKeyHandler{},
+				DispatchOverallTimeout: 30 * time.Second,
+				SecondaryDispatchDelay: tc.secondaryDelay,
+			}, map[string]SecondaryDispatch{
+				"secondary": {Name: "secondary", Client: v1.NewDispatchServiceClient(secondaryConn)},
+			}, map[string]*DispatchExpr{
+				"check": parsed,
+			})
+			require.True(t, dispatcher.ReadyState().IsReady)
+
+			_, err = dispatcher.DispatchCheck(context.Background(), &v1.DispatchCheckRequest{
+				ResourceRelation: &corev1.RelationReference{Namespace: "somenamespace", Relation: "somerelation"},
+				ResourceIds:      []string{"foo"},
+				Metadata:         &v1.ResolverMeta{DepthRemaining: 50},
+				Subject:          &corev1.ObjectAndRelation{Namespace: "foo", ObjectId: "bar", Relation: "..."},
+			})
+			require.NoError(t, err)
+			require.Len(t, secondarySvc.received, tc.expectedSecondaryRequests)
+		})
+	}
+}
+
+type failingDispatchSvc struct {
+	v1.UnimplementedDispatchServiceServer
+}
+
+func (fds *failingDispatchSvc) DispatchCheck(context.Context, *v1.DispatchCheckRequest) (*v1.DispatchCheckResponse, error) {
+	return nil, status.Error(codes.Unavailable, "primary is unavailable")
+}
+
+func TestSecondaryDispatchAfterPrimaryError(t *testing.T) {
+	for _, tc := range []struct {
+		name               string
+		secondaryDelay     time.Duration
+		secondarySleepTime time.Duration
+	}{
+		{
+			"without delay",
+			0 * time.Millisecond,
+			50 * time.Millisecond,
+		},
+		{
+			"primary fails within the delay",
+			1 * time.Second,
+			0 * time.Millisecond,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			conn := connectionForDispatching(t, &failingDispatchSvc{})
+			secondaryConn := connectionForDispatching(t, &fakeDispatchSvc{dispatchCount: 2, sleepTime: tc.secondarySleepTime})
+
+			parsed, err := ParseDispatchExpression("check", "['secondary']")
+			require.NoError(t, err)
+
+			dispatcher := NewClusterDispatcher(v1.NewDispatchServiceClient(conn), conn, ClusterDispatcherConfig{
+				KeyHandler:             &keys.DirectKeyHandler{},
+				DispatchOverallTimeout: 30 * time.Second,
+				SecondaryDispatchDelay: tc.secondaryDelay,
+			}, map[string]SecondaryDispatch{
+				"secondary": {Name: "secondary", Client: v1.NewDispatchServiceClient(secondaryConn)},
+			}, map[string]*DispatchExpr{
+				"check": parsed,
+			})
+			require.True(t, dispatcher.ReadyState().IsReady)
+
+			// The secondary succeeds after the primary has failed, so its result is returned.
+			resp, err := dispatcher.DispatchCheck(context.Background(), &v1.DispatchCheckRequest{
+				ResourceRelation: &corev1.RelationReference{Namespace: "somenamespace", Relation: "somerelation"},
+				ResourceIds:      []string{"foo"},
+				Metadata:         &v1.ResolverMeta{DepthRemaining: 50},
+				Subject:          &corev1.ObjectAndRelation{Namespace: "foo", ObjectId: "bar", Relation: "..."},
+			})
+			require.NoError(t, err)
+			require.Equal(t, uint32(2), resp.Metadata.DispatchCount)
+		})
+	}
+}
+
+func TestSecondaryDispatchAllFailReturnsPrimaryError(t *testing.T) {
+	conn := connectionForDispatching(t, &failingDispatchSvc{})
+	secondaryConn := connectionForDispatching(t, &failingDispatchSvc{})
+
+	parsed, err := ParseDispatchExpression("check", "['secondary']")
+	require.NoError(t, err)
+
+	dispatcher := NewClusterDispatcher(v1.NewDispatchServiceClient(conn), conn, ClusterDispatcherConfig{
+		KeyHandler:             &keys.DirectKeyHandler{},
+		DispatchOverallTimeout: 30 * time.Second,
+	}, map[string]SecondaryDispatch{
+		"secondary": {Name: "secondary", Client: v1.NewDispatchServiceClient(secondaryConn)},
+	}, map[string]*DispatchExpr{
+		"check": parsed,
+	})
+
+	_, err = dispatcher.DispatchCheck(context.Background(), &v1.DispatchCheckRequest{
+		ResourceRelation: &corev1.RelationReference{Namespace: "somenamespace", Relation: "somerelation"},
+		ResourceIds:      []string{"foo"},
+		Metadata:         &v1.ResolverMeta{DepthRemaining: 50},
+		Subject:          &corev1.ObjectAndRelation{Namespace: "foo", ObjectId: "bar", Relation: "..."},
+	})
+	require.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+type recordingDispatchSvc struct {
+	v1.UnimplementedDispatchServiceServer
+
+	received []*v1.ResolverMeta
+}
+
+func (rds *recordingDispatchSvc) DispatchCheck(_ context.Context, req *v1.DispatchCheckRequest) (*v1.DispatchCheckResponse, error) {
+	rds.received = append(rds.received, req.Metadata)
+	return &v1.DispatchCheckResponse{
+		Metadata: &v1.ResponseMeta{
+			DispatchCount: 1,
+		},
+	}, nil
+}
+
+func TestDispatchPropagatesResolverMetadata(t *testing.T) {
+	svc := &recordingDispatchSvc{}
+	conn := connectionForDispatching(t, svc)
+
+	dispatcher := NewClusterDispatcher(v1.NewDispatchServiceClient(conn), conn, ClusterDispatcherConfig{
+		KeyHandler:             &keys.DirectKeyHandler{},
+		DispatchOverallTimeout: 30 * time.Second,
+	}, nil, nil)
+	require.True(t, dispatcher.ReadyState().IsReady)
+
+	request := func(depthRemaining uint32) *v1.DispatchCheckRequest {
+		return &v1.DispatchCheckRequest{
+			ResourceRelation: &corev1.RelationReference{Namespace: "sometype", Relation: "somerel"},
+			ResourceIds:      []string{"foo"},
+			Metadata:         &v1.ResolverMeta{AtRevision: "1234", DepthRemaining: depthRemaining},
+			Subject:          &corev1.ObjectAndRelation{Namespace: "foo", ObjectId: "bar", Relation: "..."},
+		}
+	}
+
+	// The revision and remaining depth must reach the peer unchanged.
+	_, err := dispatcher.DispatchCheck(context.Background(), request(7))
+	require.NoError(t, err)
+	require.Len(t, svc.received, 1)
+	require.Equal(t, "1234", svc.received[0].AtRevision)
+	require.Equal(t, uint32(7), svc.received[0].DepthRemaining)
+
+	// An exhausted depth must be rejected before the request leaves the node.
+	_, err = dispatcher.DispatchCheck(context.Background(), request(0))
+	require.ErrorAs(t, err, &dispatch.MaxDepthExceededError{})
+	require.Len(t, svc.received, 1)
+}
+
 func connectionForDispatching(t *testing.T, svc v1.DispatchServiceServer) *grpc.ClientConn {
 	listener := bufconn.Listen(humanize.MiByte)
 	s := grpc.NewServer()
@@ -708,12 +708,18 @@ func all[T any](
 
 	resultChan := make(chan CheckResult, len(children))
 	childCtx, cancelFn := context.WithCancel(ctx)
-	dispatchAllAsync(childCtx, currentRequestContext{
+
+	// A child with no members makes the intersection empty, so the remaining children
+	// need not be dispatched. The result is published before the others are canceled,
+	// ensuring it is received ahead of any result produced by the cancelation.
+	dispatchAllAsyncUntil(childCtx, currentRequestContext{
 		parentReq:           crc.parentReq,
 		filteredResourceIDs: crc.filteredResourceIDs,
 		resultsSetting:      v1.DispatchCheckRequest_REQUIRE_ALL_RESULTS,
 		maxDispatchCount:    crc.maxDispatchCount,
-	}, children, handler, resultChan, concurrencyLimit)
+	}, children, handler, resultChan, concurrencyLimit, func(result CheckResult) bool {
+		return len(result.Resp.ResultsByResourceId) == 0
+	})
 	defer cancelFn()
 
 	var membershipSet *MembershipSet
@@ -827,6 +833,25 @@ func dispatchAllAsync[T any](
 	handler func(ctx context.Context, crc currentRequestContext, child T) CheckResult,
 	resultChan chan<- CheckResult,
 	concurrencyLimit uint16,
+) {
+	dispatchAllAsyncUntil(ctx, crc, children, handler, resultChan, concurrencyLimit, nil)
+}
+
+// errShortCircuited is returned by a dispatched task to stop the task runner from
+// starting any further tasks, once the result of the overall operation is known.
+var errShortCircuited = errors.New("short circuited")
+
+// dispatchAllAsyncUntil dispatches the children like dispatchAllAsync, but once a result
+// for which isDecisive returns true has been published, any children not yet started
+// are skipped and those in flight are canceled.
+func dispatchAllAsyncUntil[T any](
+	ctx context.Context,
+	crc currentRequestContext,
+	children []T,
+	handler func(ctx context.Context, crc currentRequestContext, child T) CheckResult,
+	resultChan chan<- CheckResult,
+	concurrencyLimit uint16,
+	isDecisive func(result CheckResult) bool,
 ) {
 	tr := taskrunner.NewPreloadedTaskRunner(ctx, concurrencyLimit, len(children))
 	for _, currentChild := range children {
@@ -834,6 +859,9 @@ func dispatchAllAsync[T any](
 		tr.Add(func(ctx context.Context) error {
 			result := handler(ctx, crc, currentChild)
 			resultChan <- result
+			if result.Err == nil && isDecisive != nil && isDecisive(result) {
+				return errShortCircuited
+			}
 			return result.Err
 		})
 	}
@@ -3,6 +3,7 @@ package computed_test
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"testing"
 
 	"google.golang.org/protobuf/types/known/structpb"
@@ -14,6 +15,7 @@ import (
 	datastoremw "github.com/authzed/spicedb/internal/middleware/datastore"
 	"github.com/authzed/spicedb/pkg/caveats/types"
 	"github.com/authzed/spicedb/pkg/datastore"
+	"github.com/authzed/spicedb/pkg/datastore/options"
 	core "github.com/authzed/spicedb/pkg/proto/core/v1"
 	v1 "github.com/authzed/spicedb/pkg/proto/dispatch/v1"
 	"github.com/authzed/spicedb/pkg/schemadsl/compiler"
@@ -976,3 +978,104 @@ func caveatedRelationTuple(relationTuple string, caveatName string, context map[
 	}
 	return c
 }
+
+func BenchmarkComputeBulkCheckSharedSubject(b *testing.B) {
+	ds, err := memdb.NewMemdbDatastore(0, 0, memdb.DisableGC)
+	require.NoError(b, err)
+
+	dispatch := graph.NewLocalOnlyDispatcher(10)
+	counting := &queryCountingDatastore{Datastore: ds}
+	ctx := datastoremw.ContextWithHandle(context.Background())
+	require.NoError(b, datastoremw.SetInContext(ctx, counting))
+
+	const numResources = 500
+
+	updates := []caveatedUpdate{
+		{core.RelationTupleUpdate_CREATE, "group:engineering#member@user:tom", "", nil},
+	}
+	resourceIDs := make([]string, 0, numResources)
+	for i := 0; i < numResources; i++ {
+		resourceID := fmt.Sprintf("doc-%d", i)
+		resourceIDs = append(resourceIDs, resourceID)
+
+		// Only every other document is reachable, to ensure both outcomes are computed.
+		if i%2 == 0 {
+			updates = append(updates, caveatedUpdate{core.RelationTupleUpdate_CREATE, fmt.Sprintf("document:%s#viewer@group:engineering#member", resourceID), "", nil})
+		}
+	}
+
+	revision, err := writeCaveatedTuples(ctx, nil, ds, `
+	definition user {}
+
+	definition group {
+		relation member: user
+	}
+
+	definition document {
+		relation viewer: user | group#member
+		permission view = viewer
+	}
+	`, updates)
+	require.NoError(b, err)
+
+	params := computed.CheckParameters{
+		ResourceType: &core.RelationReference{
+			Namespace: "document",
+			Relation:  "view",
+		},
+		Subject: &core.ObjectAndRelation{
+			Namespace: "user",
+			ObjectId:  "tom",
+			Relation:  "...",
+		},
+		AtRevision:   revision,
+		MaximumDepth: 50,
+		DebugOption:  computed.NoDebugging,
+	}
+
+	b.Run("per item", func(b *testing.B) {
+		counting.queries.Store(0)
+		for n := 0; n < b.N; n++ {
+			for _, resourceID := range resourceIDs {
+				_, _, err := computed.ComputeCheck(ctx, dispatch, params, resourceID)
+				require.NoError(b, err)
+			}
+		}
+		b.ReportMetric(float64(counting.queries.Load())/float64(b.N), "queries/op")
+	})
+
+	b.Run("batched", func(b *testing.B) {
+		counting.queries.Store(0)
+		for n := 0; n < b.N; n++ {
+			results, _, err := computed.ComputeBulkCheck(ctx, dispatch, params, resourceIDs)
+			require.NoError(b, err)
+			require.Len(b, results, numResources)
+		}
+		b.ReportMetric(float64(counting.queries.Load())/float64(b.N), "queries/op")
+	})
+}
+
+// queryCountingDatastore counts the relationship queries issued against its snapshot readers.
+type queryCountingDatastore struct {
+	datastore.Datastore
+	queries atomic.Uint64
+}
+
+func (qcd *queryCountingDatastore) SnapshotReader(rev datastore.Revision) datastore.Reader {
+	return queryCountingReader{qcd.Datastore.SnapshotReader(rev), &qcd.queries}
+}
+
+type queryCountingReader struct {
+	datastore.Reader
+	queries *atomic.Uint64
+}
+
+func (qcr queryCountingReader) QueryRelationships(ctx context.Context, filter datastore.RelationshipsFilter, opts ...options.QueryOptionsOption) (datastore.RelationshipIterator, error) {
+	qcr.queries.Add(1)
+	return qcr.Reader.QueryRelationships(ctx, filter, opts...)
+}
+
+func (qcr queryCountingReader) ReverseQueryRelationships(ctx context.Context, subjectsFilter datastore.SubjectsFilter, opts ...options.ReverseQueryOptionsOption) (datastore.RelationshipIterator, error) {
+	qcr.queries.Add(1)
+	return qcr.Reader.ReverseQueryRelationships(ctx, subjectsFilter, opts...)
+}
@@ -57,14 +57,39 @@ func (m *MiddlewareForTesting) getOrCreateDatastore(ctx context.Context) (datast
 
 	_, _, err = validationfile.PopulateFromFiles(ctx, ds, m.configFilePaths)
 	if err != nil {
+		ds.Close()
 		return nil, fmt.Errorf("failed to load config files: %w", err)
 	}
 
 	// Squash the revisions so that the caller sees all the populated data.
 	ds.(squashable).SquashRevisionsForTesting()
 
-	m.datastoreByToken.Store(tokenStr, ds)
-	return ds, nil
+	return m.storeOrClose(tokenStr, ds), nil
+}
+
+// storeOrClose stores the datastore for the token and returns it. If another request
+// for the same token stored a datastore concurrently, that one is returned instead and
+// the given datastore is closed, so that its garbage collector is stopped.
+func (m *MiddlewareForTesting) storeOrClose(tokenStr string, ds datastore.Datastore) datastore.Datastore {
+	tokenDatastore, loaded := m.datastoreByToken.LoadOrStore(tokenStr, ds)
+	if loaded {
+		ds.Close()
+	}
+
+	return tokenDatastore.(datastore.Datastore)
+}
+
+// Close closes all of the datastores created by the middleware.
+func (m *MiddlewareForTesting) Close() error {
+	var closeErr error
+	m.datastoreByToken.Range(func(key, value any) bool {
+		if err := value.(datastore.Datastore).Close(); err != nil {
+			closeErr = err
+		}
+		m.datastoreByToken.Delete(key)
+		return true
+	})
+	return closeErr
 }
 
 // UnaryServerInterceptor returns a new unary server interceptor that sets a separate in-memory datastore per token
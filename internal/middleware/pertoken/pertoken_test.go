@@ -0,0 +1,50 @@
+package pertoken
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/authzed/spicedb/internal/datastore/memdb"
+	datastoremw "github.com/authzed/spicedb/internal/middleware/datastore"
+	"github.com/authzed/spicedb/pkg/datastore"
+)
+
+func TestMiddlewareClosesDatastores(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	m := NewMiddleware(nil)
+	interceptor := m.UnaryServerInterceptor()
+
+	call := func(token string) datastore.Datastore {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "bearer "+token))
+
+		var found datastore.Datastore
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ any) (any, error) {
+			found = datastoremw.MustFromContext(ctx)
+			return nil, nil
+		})
+		require.NoError(t, err)
+		return found
+	}
+
+	first := call("sometoken")
+	require.Same(t, first, call("sometoken"))
+	require.NotSame(t, first, call("anothertoken"))
+
+	// A datastore created by a request that lost the race to create the datastore for
+	// its token is closed, and the winner is returned.
+	loser, err := memdb.NewMemdbDatastore(0, revisionQuantization, gcWindow)
+	require.NoError(t, err)
+	require.Same(t, first, m.storeOrClose("sometoken", loser))
+
+	_, err = loser.HeadRevision(context.Background())
+	require.Error(t, err)
+
+	// Closing the middleware stops the garbage collector of every datastore it created.
+	require.NoError(t, m.Close())
+}
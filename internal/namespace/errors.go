@@ -2,11 +2,13 @@ package namespace
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/rs/zerolog"
 
 	"github.com/authzed/spicedb/internal/sharederrors"
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
 )
 
 // ErrNamespaceNotFound occurs when a namespace was not found.
@@ -35,8 +37,9 @@ func (err ErrNamespaceNotFound) DetailsMetadata() map[string]string {
 // ErrRelationNotFound occurs when a relation was not found under a namespace.
 type ErrRelationNotFound struct {
 	error
-	namespaceName string
-	relationName  string
+	namespaceName          string
+	relationName           string
+	availableRelationNames []string
 }
 
 // NamespaceName returns the name of the namespace in which the relation was not found.
@@ -55,10 +58,16 @@ func (err ErrRelationNotFound) MarshalZerologObject(e *zerolog.Event) {
 
 // DetailsMetadata returns the metadata for details for this error.
 func (err ErrRelationNotFound) DetailsMetadata() map[string]string {
-	return map[string]string{
+	metadata := map[string]string{
 		"definition_name":             err.namespaceName,
 		"relation_or_permission_name": err.relationName,
 	}
+
+	if len(err.availableRelationNames) > 0 {
+		metadata["available_relation_or_permission_names"] = strings.Join(err.availableRelationNames, ",")
+	}
+
+	return metadata
 }
 
 // ErrDuplicateRelation occurs when a duplicate relation was found inside a namespace.
@@ -138,6 +147,20 @@ func NewRelationNotFoundErr(nsName string, relationName string) error {
 	}
 }
 
+// newRelationNotFoundErrForDefinition constructs a new relation not found error which
+// lists the relations and permissions defined under the namespace.
+func newRelationNotFoundErrForDefinition(nsDef *core.NamespaceDefinition, relationName string) error {
+	availableRelationNames := make([]string, 0, len(nsDef.Relation))
+	for _, rel := range nsDef.Relation {
+		availableRelationNames = append(availableRelationNames, rel.Name)
+	}
+	sort.Strings(availableRelationNames)
+
+	err := NewRelationNotFoundErr(nsDef.Name, relationName).(ErrRelationNotFound)
+	err.availableRelationNames = availableRelationNames
+	return err
+}
+
 // NewDuplicateRelationError constructs an error indicating that a relation was defined more than once in a namespace.
 func NewDuplicateRelationError(nsName string, relationName string) error {
 	return ErrDuplicateRelation{
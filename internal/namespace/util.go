@@ -32,7 +32,7 @@ func ReadNamespaceAndRelation(
 		}
 	}
 
-	return nil, nil, NewRelationNotFoundErr(namespace, relation)
+	return nil, nil, newRelationNotFoundErrForDefinition(config, relation)
 }
 
 // TypeAndRelationToCheck is a single check of a namespace+relation pair.
@@ -91,7 +91,7 @@ func CheckNamespaceAndRelations(ctx context.Context, checks []TypeAndRelationToC
 		}
 
 		if !foundRelation {
-			return NewRelationNotFoundErr(toCheck.NamespaceName, toCheck.RelationName)
+			return newRelationNotFoundErrForDefinition(nsDef, toCheck.RelationName)
 		}
 	}
 
@@ -126,7 +126,7 @@ func CheckNamespaceAndRelation(
 		}
 	}
 
-	return NewRelationNotFoundErr(namespace, relation)
+	return newRelationNotFoundErrForDefinition(config, relation)
 }
 
 // ReadNamespaceAndTypes reads a namespace definition, version, and type system and returns it if found.
@@ -13,14 +13,18 @@ import (
 	"github.com/authzed/spicedb/pkg/datastore"
 )
 
-const datastoreReadyTimeout = time.Millisecond * 500
+const (
+	datastoreReadyTimeout = time.Millisecond * 500
+	readyRecheckInterval  = time.Second * 5
+)
 
 // NewHealthManager creates and returns a new health manager that checks the IsReady
 // status of the given dispatcher and datastore checker and sets the health check to
-// return healthy once both have gone to true.
+// return healthy once both have gone to true. Once healthy, readiness is periodically
+// rechecked and the health check returns unhealthy again if either stops being ready.
 func NewHealthManager(dispatcher dispatch.Dispatcher, dsc DatastoreChecker) Manager {
 	healthSvc := grpcutil.NewAuthlessHealthServer()
-	return &healthManager{healthSvc, dispatcher, dsc, map[string]struct{}{}}
+	return &healthManager{healthSvc, dispatcher, dsc, map[string]struct{}{}, readyRecheckInterval}
 }
 
 // DatastoreChecker is an interface for determining if the datastore is ready for
@@ -40,6 +44,7 @@ type Manager interface {
 	HealthSvc() *grpcutil.AuthlessHealthServer
 
 	// Checker returns a function that can be run via an errgroup to perform the health checks.
+	// The function runs until the given context is canceled.
 	Checker(ctx context.Context) func() error
 }
 
@@ -48,6 +53,8 @@ type healthManager struct {
 	dispatcher   dispatch.Dispatcher
 	dsc          DatastoreChecker
 	serviceNames map[string]struct{}
+
+	recheckInterval time.Duration
 }
 
 func (hm *healthManager) HealthSvc() *grpcutil.AuthlessHealthServer {
@@ -81,11 +88,13 @@ func (hm *healthManager) Checker(ctx context.Context) func() error {
 			}
 
 			isReady := hm.checkIsReady(ctx)
+			hm.setServingStatus(isReady)
 			if isReady {
-				for serviceName := range hm.serviceNames {
-					hm.healthSvc.Server.SetServingStatus(serviceName, healthpb.HealthCheckResponse_SERVING)
-				}
-				return nil
+				// Keep rechecking so that the services are reported as not serving
+				// if the datastore or dispatcher later stop being ready.
+				backoffInterval.Reset()
+				ticker = time.After(hm.recheckInterval)
+				continue
 			}
 
 			nextPush := backoffInterval.NextBackOff()
@@ -98,6 +107,17 @@ func (hm *healthManager) Checker(ctx context.Context) func() error {
 	}
 }
 
+func (hm *healthManager) setServingStatus(isReady bool) {
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if isReady {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+
+	for serviceName := range hm.serviceNames {
+		hm.healthSvc.Server.SetServingStatus(serviceName, status)
+	}
+}
+
 func (hm *healthManager) checkIsReady(ctx context.Context) bool {
 	log.Ctx(ctx).Debug().Msg("checking if datastore and dispatcher are ready")
 
@@ -0,0 +1,74 @@
+package health
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/authzed/spicedb/internal/dispatch/graph"
+	"github.com/authzed/spicedb/pkg/datastore"
+)
+
+type fakeDatastoreChecker struct {
+	ready atomic.Bool
+}
+
+func (fdc *fakeDatastoreChecker) ReadyState(_ context.Context) (datastore.ReadyState, error) {
+	if !fdc.ready.Load() {
+		return datastore.ReadyState{Message: "not yet ready"}, nil
+	}
+	return datastore.ReadyState{IsReady: true}, nil
+}
+
+func TestHealthManagerServesOnceDatastoreIsReady(t *testing.T) {
+	require := require.New(t)
+
+	dsc := &fakeDatastoreChecker{}
+	manager := NewHealthManager(graph.NewLocalOnlyDispatcher(1), dsc)
+	manager.(*healthManager).recheckInterval = 10 * time.Millisecond
+	manager.RegisterReportedService("someservice")
+
+	status := func() healthpb.HealthCheckResponse_ServingStatus {
+		resp, err := manager.HealthSvc().Check(context.Background(), &healthpb.HealthCheckRequest{Service: "someservice"})
+		require.NoError(err)
+		return resp.Status
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- manager.Checker(ctx)()
+	}()
+
+	// The service must not be reported as serving while the datastore is not ready.
+	require.Never(func() bool {
+		return status() == healthpb.HealthCheckResponse_SERVING
+	}, 100*time.Millisecond, 10*time.Millisecond)
+	require.Equal(healthpb.HealthCheckResponse_NOT_SERVING, status())
+
+	dsc.ready.Store(true)
+
+	require.Eventually(func() bool {
+		return status() == healthpb.HealthCheckResponse_SERVING
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// A datastore outage must flip the service back to not serving.
+	dsc.ready.Store(false)
+	require.Eventually(func() bool {
+		return status() == healthpb.HealthCheckResponse_NOT_SERVING
+	}, 5*time.Second, 10*time.Millisecond)
+
+	dsc.ready.Store(true)
+	require.Eventually(func() bool {
+		return status() == healthpb.HealthCheckResponse_SERVING
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.NoError(<-done)
+}
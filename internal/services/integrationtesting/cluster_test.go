@@ -0,0 +1,177 @@
+//go:build !skipintegrationtests
+// +build !skipintegrationtests
+
+package integrationtesting_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	v1 "github.com/authzed/authzed-go/proto/authzed/api/v1"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/authzed/spicedb/internal/datastore/memdb"
+	tf "github.com/authzed/spicedb/internal/testfixtures"
+	"github.com/authzed/spicedb/internal/testserver"
+	"github.com/authzed/spicedb/pkg/zedtoken"
+)
+
+// TestClusterDispatchMatchesLocalDispatch ensures that checks whose subproblems are dispatched
+// across the nodes of a cluster return the same results as a server dispatching locally.
+func TestClusterDispatchMatchesLocalDispatch(t *testing.T) {
+	require := require.New(t)
+
+	rawDS, err := memdb.NewMemdbDatastore(0, 0, memdb.DisableGC)
+	require.NoError(err)
+
+	ds, revision := tf.StandardDatastoreWithData(rawDS, require)
+	clusterConns, cleanup := testserver.TestClusterWithDispatch(t, 2, ds)
+	t.Cleanup(cleanup)
+
+	localConn, localCleanup, _, localRevision := testserver.NewTestServer(require, 0, memdb.DisableGC, true, tf.StandardDatastoreWithData)
+	t.Cleanup(localCleanup)
+
+	check := func(client v1.PermissionsServiceClient, atRevision *v1.ZedToken, resourceType, resourceID, permission, userID string) v1.CheckPermissionResponse_Permissionship {
+		resp, err := client.CheckPermission(context.Background(), &v1.CheckPermissionRequest{
+			Consistency: &v1.Consistency{
+				Requirement: &v1.Consistency_AtLeastAsFresh{AtLeastAsFresh: atRevision},
+			},
+			Resource: &v1.ObjectReference{
+				ObjectType: resourceType,
+				ObjectId:   resourceID,
+			},
+			Permission: permission,
+			Subject: &v1.SubjectReference{
+				Object: &v1.ObjectReference{
+					ObjectType: "user",
+					ObjectId:   userID,
+				},
+			},
+		})
+		require.NoError(err)
+		return resp.Permissionship
+	}
+
+	localClient := v1.NewPermissionsServiceClient(localConn)
+	resources := []struct {
+		resourceType string
+		resourceID   string
+		permission   string
+	}{
+		{"document", "masterplan", "view"},
+		{"document", "masterplan", "edit"},
+		{"document", "healthplan", "view"},
+		{"document", "companyplan", "view"},
+		{"folder", "company", "view"},
+		{"folder", "strategy", "view"},
+	}
+	users := []string{"owner", "legal", "vp_product", "product_manager", "eng_lead", "chief_financial_officer", "auditor", "villain"}
+
+	foundPermission := false
+	for _, resource := range resources {
+		for _, userID := range users {
+			expected := check(localClient, zedtoken.MustNewFromRevision(localRevision), resource.resourceType, resource.resourceID, resource.permission, userID)
+			foundPermission = foundPermission || expected == v1.CheckPermissionResponse_PERMISSIONSHIP_HAS_PERMISSION
+
+			for index, conn := range clusterConns {
+				found := check(v1.NewPermissionsServiceClient(conn), zedtoken.MustNewFromRevision(revision), resource.resourceType, resource.resourceID, resource.permission, userID)
+				require.Equal(expected, found, fmt.Sprintf("mismatch on node %d for %s:%s#%s@user:%s", index, resource.resourceType, resource.resourceID, resource.permission, userID))
+			}
+		}
+	}
+
+	// Sanity check the fixture grants permissions.
+	require.True(foundPermission)
+}
+
+// TestClusterDispatchPropagatesTraceContext ensures that the spans of the dispatch server on the
+// node receiving a streaming dispatch are children of the span of the dispatching node's client.
+func TestClusterDispatchPropagatesTraceContext(t *testing.T) {
+	require := require.New(t)
+
+	spanrecorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithSpanProcessor(spanrecorder),
+	)
+
+	defaultProvider := otel.GetTracerProvider()
+	defaultPropagator := otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(defaultProvider)
+		otel.SetTextMapPropagator(defaultPropagator)
+
+		// Tracers obtained before this test remain bound to the provider, so shut it
+		// down to stop recording the spans of the tests that follow.
+		require.NoError(provider.Shutdown(context.Background()))
+	})
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	rawDS, err := memdb.NewMemdbDatastore(0, 0, memdb.DisableGC)
+	require.NoError(err)
+
+	ds, revision := tf.StandardDatastoreWithData(rawDS, require)
+	clusterConns, cleanup := testserver.TestClusterWithDispatch(t, 2, ds)
+	t.Cleanup(cleanup)
+
+	stream, err := v1.NewPermissionsServiceClient(clusterConns[0]).LookupResources(context.Background(), &v1.LookupResourcesRequest{
+		Consistency: &v1.Consistency{
+			Requirement: &v1.Consistency_AtLeastAsFresh{AtLeastAsFresh: zedtoken.MustNewFromRevision(revision)},
+		},
+		ResourceObjectType: "document",
+		Permission:         "view",
+		Subject: &v1.SubjectReference{
+			Object: &v1.ObjectReference{
+				ObjectType: "user",
+				ObjectId:   "legal",
+			},
+		},
+	})
+	require.NoError(err)
+
+	foundResource := false
+	for {
+		_, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(err)
+		foundResource = true
+	}
+	require.True(foundResource)
+
+	const method = "dispatch.v1.DispatchService/DispatchLookupResources"
+	clientSpans := make(map[trace.SpanID]sdktrace.ReadOnlySpan)
+	var serverSpans []sdktrace.ReadOnlySpan
+	for _, span := range spanrecorder.Ended() {
+		if span.Name() != method {
+			continue
+		}
+
+		switch span.SpanKind() {
+		case trace.SpanKindClient:
+			clientSpans[span.SpanContext().SpanID()] = span
+		case trace.SpanKindServer:
+			serverSpans = append(serverSpans, span)
+		}
+	}
+	require.NotEmpty(clientSpans)
+	require.NotEmpty(serverSpans)
+
+	for _, span := range serverSpans {
+		parent, ok := clientSpans[span.Parent().SpanID()]
+		require.True(ok, "server span %s has no dispatch client span as its parent", span.SpanContext().SpanID())
+		require.True(span.Parent().IsRemote())
+		require.Equal(parent.SpanContext().TraceID(), span.SpanContext().TraceID())
+	}
+}
@@ -78,12 +78,18 @@ type AppliedSchemaChanges struct {
 	// NewObjectDefNames contains the names of the newly added object definitions.
 	NewObjectDefNames []string
 
+	// ModifiedObjectDefNames contains the names of the existing object definitions that were changed.
+	ModifiedObjectDefNames []string
+
 	// RemovedObjectDefNames contains the names of the removed object definitions.
 	RemovedObjectDefNames []string
 
 	// NewCaveatDefNames contains the names of the newly added caveat definitions.
 	NewCaveatDefNames []string
 
+	// ModifiedCaveatDefNames contains the names of the existing caveat definitions that were changed.
+	ModifiedCaveatDefNames []string
+
 	// RemovedCaveatDefNames contains the names of the removed caveat definitions.
 	RemovedCaveatDefNames []string
 }
@@ -104,6 +110,56 @@ func ApplySchemaChanges(ctx context.Context, rwt datastore.ReadWriteTransaction,
 	return ApplySchemaChangesOverExisting(ctx, rwt, validated, datastore.DefinitionsOf(existingCaveats), datastore.DefinitionsOf(existingObjectDefs))
 }
 
+// SchemaChangesAreNoop returns whether applying the validated schema changes over the schema
+// found by the reader would leave every definition unchanged, in which case the write can be
+// skipped entirely.
+func SchemaChangesAreNoop(ctx context.Context, reader datastore.Reader, validated *ValidatedSchemaChanges) (bool, error) {
+	existingObjectDefs, existingCaveats, err := readSchemaDefinitions(ctx, reader)
+	if err != nil {
+		return false, err
+	}
+
+	// Definitions missing from the new schema are only removed if not additive.
+	if !validated.additiveOnly &&
+		(len(existingObjectDefs) != validated.newObjectDefNames.Len() || len(existingCaveats) != validated.newCaveatDefNames.Len()) {
+		return false, nil
+	}
+
+	for _, caveatDef := range validated.compiled.CaveatDefinitions {
+		existing, ok := existingCaveats[caveatDef.Name]
+		if !ok {
+			return false, nil
+		}
+
+		diff, err := caveatdiff.DiffCaveats(existing, caveatDef)
+		if err != nil {
+			return false, err
+		}
+
+		if len(diff.Deltas()) > 0 {
+			return false, nil
+		}
+	}
+
+	for _, nsdef := range validated.compiled.ObjectDefinitions {
+		existing, ok := existingObjectDefs[nsdef.Name]
+		if !ok {
+			return false, nil
+		}
+
+		diff, err := nsdiff.DiffNamespaces(existing, nsdef)
+		if err != nil {
+			return false, err
+		}
+
+		if len(diff.Deltas()) > 0 {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
 // ApplySchemaChangesOverExisting applies schema changes found in the validated changes struct, against
 // existing caveat and object definitions given.
 func ApplySchemaChangesOverExisting(
@@ -217,12 +273,28 @@ func ApplySchemaChangesOverExisting(
 		Object("removedCaveatDefinitions", removedCaveatDefNames).
 		Msg("completed schema update")
 
+	modifiedObjectDefNames := make([]string, 0, len(objectDefsWithChanges))
+	for _, nsdef := range objectDefsWithChanges {
+		if existingObjectDefNames.Has(nsdef.Name) {
+			modifiedObjectDefNames = append(modifiedObjectDefNames, nsdef.Name)
+		}
+	}
+
+	modifiedCaveatDefNames := make([]string, 0, len(caveatDefsWithChanges))
+	for _, caveatDef := range caveatDefsWithChanges {
+		if existingCaveatDefNames.Has(caveatDef.Name) {
+			modifiedCaveatDefNames = append(modifiedCaveatDefNames, caveatDef.Name)
+		}
+	}
+
 	return &AppliedSchemaChanges{
-		TotalOperationCount:   uint32(len(validated.compiled.ObjectDefinitions) + len(validated.compiled.CaveatDefinitions) + removedObjectDefNames.Len() + removedCaveatDefNames.Len()),
-		NewObjectDefNames:     validated.newObjectDefNames.Subtract(existingObjectDefNames).AsSlice(),
-		RemovedObjectDefNames: removedObjectDefNames.AsSlice(),
-		NewCaveatDefNames:     validated.newCaveatDefNames.Subtract(existingCaveatDefNames).AsSlice(),
-		RemovedCaveatDefNames: removedCaveatDefNames.AsSlice(),
+		TotalOperationCount:    uint32(len(validated.compiled.ObjectDefinitions) + len(validated.compiled.CaveatDefinitions) + removedObjectDefNames.Len() + removedCaveatDefNames.Len()),
+		NewObjectDefNames:      validated.newObjectDefNames.Subtract(existingObjectDefNames).AsSlice(),
+		ModifiedObjectDefNames: modifiedObjectDefNames,
+		RemovedObjectDefNames:  removedObjectDefNames.AsSlice(),
+		NewCaveatDefNames:      validated.newCaveatDefNames.Subtract(existingCaveatDefNames).AsSlice(),
+		ModifiedCaveatDefNames: modifiedCaveatDefNames,
+		RemovedCaveatDefNames:  removedCaveatDefNames.AsSlice(),
 	}, nil
 }
 
@@ -404,3 +476,100 @@ func errorIfTupleIteratorReturnsTuples(_ context.Context, qy datastore.Relations
 	}
 	return nil
 }
+
+// SchemaDiff holds the differences between the schema found at two revisions, keyed by the name
+// of the definition. Definitions that are unchanged between the revisions are not included.
+type SchemaDiff struct {
+	NamespaceDiffs map[string]*nsdiff.Diff
+	CaveatDiffs    map[string]*caveatdiff.Diff
+}
+
+// DiffSchemaAtRevisions computes the differences between the schema stored in the datastore at
+// the existing revision and the schema stored at the updated revision. Returns an error if
+// either revision is no longer valid for the datastore, such as being outside of the GC window.
+func DiffSchemaAtRevisions(ctx context.Context, ds datastore.Datastore, existingRevision, updatedRevision datastore.Revision) (*SchemaDiff, error) {
+	for _, revision := range []datastore.Revision{existingRevision, updatedRevision} {
+		if err := ds.CheckRevision(ctx, revision); err != nil {
+			return nil, err
+		}
+	}
+
+	existingNamespaces, existingCaveats, err := readSchemaDefinitions(ctx, ds.SnapshotReader(existingRevision))
+	if err != nil {
+		return nil, err
+	}
+
+	updatedNamespaces, updatedCaveats, err := readSchemaDefinitions(ctx, ds.SnapshotReader(updatedRevision))
+	if err != nil {
+		return nil, err
+	}
+
+	schemaDiff := &SchemaDiff{
+		NamespaceDiffs: map[string]*nsdiff.Diff{},
+		CaveatDiffs:    map[string]*caveatdiff.Diff{},
+	}
+
+	nsNames := mapz.NewSet[string]()
+	for name := range existingNamespaces {
+		nsNames.Insert(name)
+	}
+	for name := range updatedNamespaces {
+		nsNames.Insert(name)
+	}
+
+	for _, name := range nsNames.AsSlice() {
+		diff, err := nsdiff.DiffNamespaces(existingNamespaces[name], updatedNamespaces[name])
+		if err != nil {
+			return nil, err
+		}
+
+		if len(diff.Deltas()) > 0 {
+			schemaDiff.NamespaceDiffs[name] = diff
+		}
+	}
+
+	caveatNames := mapz.NewSet[string]()
+	for name := range existingCaveats {
+		caveatNames.Insert(name)
+	}
+	for name := range updatedCaveats {
+		caveatNames.Insert(name)
+	}
+
+	for _, name := range caveatNames.AsSlice() {
+		diff, err := caveatdiff.DiffCaveats(existingCaveats[name], updatedCaveats[name])
+		if err != nil {
+			return nil, err
+		}
+
+		if len(diff.Deltas()) > 0 {
+			schemaDiff.CaveatDiffs[name] = diff
+		}
+	}
+
+	return schemaDiff, nil
+}
+
+func readSchemaDefinitions(ctx context.Context, reader datastore.Reader) (map[string]*core.NamespaceDefinition, map[string]*core.CaveatDefinition, error) {
+	nsDefs, err := reader.ListAllNamespaces(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	caveatDefs, err := reader.ListAllCaveats(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	namespaces := make(map[string]*core.NamespaceDefinition, len(nsDefs))
+	for _, nsDef := range nsDefs {
+		namespaces[nsDef.Definition.Name] = nsDef.Definition
+	}
+
+	caveats := make(map[string]*core.CaveatDefinition, len(caveatDefs))
+	for _, caveatDef := range caveatDefs {
+		caveats[caveatDef.Definition.Name] = caveatDef.Definition
+	}
+
+	return namespaces, caveats, nil
+}
@@ -3,12 +3,15 @@ package shared
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/authzed/spicedb/internal/datastore/memdb"
+	"github.com/authzed/spicedb/internal/datastore/revisions"
 	"github.com/authzed/spicedb/internal/testfixtures"
 	"github.com/authzed/spicedb/pkg/datastore"
+	nsdiff "github.com/authzed/spicedb/pkg/diff/namespace"
 	"github.com/authzed/spicedb/pkg/schemadsl/compiler"
 	"github.com/authzed/spicedb/pkg/schemadsl/input"
 )
@@ -55,10 +58,131 @@ func TestApplySchemaChanges(t *testing.T) {
 		require.NoError(err)
 
 		require.Equal(applied.NewObjectDefNames, []string{"organization"})
+		require.Empty(applied.ModifiedObjectDefNames)
 		require.Equal(applied.RemovedObjectDefNames, []string{"document"})
 		require.Equal(applied.NewCaveatDefNames, []string{"catchTwentyTwo"})
+		require.Empty(applied.ModifiedCaveatDefNames)
 		require.Equal(applied.RemovedCaveatDefNames, []string{"hasFortyTwo"})
 		return nil
 	})
 	require.NoError(err)
 }
+
+func TestApplySchemaChangesReportsModifiedDefinitions(t *testing.T) {
+	require := require.New(t)
+	rawDS, err := memdb.NewMemdbDatastore(0, 0, memdb.DisableGC)
+	require.NoError(err)
+
+	// Write the initial schema.
+	ds, _ := testfixtures.DatastoreFromSchemaAndTestRelationships(rawDS, `
+		definition user {}
+
+		definition document {
+			relation viewer: user
+			permission view = viewer
+		}
+
+		caveat hasFortyTwo(value int) {
+          value == 42
+        }
+	`, nil, require)
+
+	// Change the document definition and the caveat, leaving the user definition untouched.
+	compiled, err := compiler.Compile(compiler.InputSchema{
+		Source: input.Source("schema"),
+		SchemaString: `
+			definition user {}
+
+			definition document {
+				relation viewer: user
+				relation editor: user
+				permission view = viewer + editor
+			}
+
+			caveat hasFortyTwo(value int) {
+			  value >= 42
+			}
+		`,
+	}, compiler.AllowUnprefixedObjectType())
+	require.NoError(err)
+
+	validated, err := ValidateSchemaChanges(context.Background(), compiled, false)
+	require.NoError(err)
+
+	_, err = ds.ReadWriteTx(context.Background(), func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+		applied, err := ApplySchemaChanges(context.Background(), rwt, validated)
+		require.NoError(err)
+
+		require.Empty(applied.NewObjectDefNames)
+		require.Equal([]string{"document"}, applied.ModifiedObjectDefNames)
+		require.Empty(applied.RemovedObjectDefNames)
+		require.Empty(applied.NewCaveatDefNames)
+		require.Equal([]string{"hasFortyTwo"}, applied.ModifiedCaveatDefNames)
+		require.Empty(applied.RemovedCaveatDefNames)
+		return nil
+	})
+	require.NoError(err)
+}
+
+func TestDiffSchemaAtRevisions(t *testing.T) {
+	require := require.New(t)
+	rawDS, err := memdb.NewMemdbDatastore(0, 0, time.Hour)
+	require.NoError(err)
+
+	// Write the initial schema.
+	ds, existingRevision := testfixtures.DatastoreFromSchemaAndTestRelationships(rawDS, `
+		definition user {}
+
+		definition document {
+			relation viewer: user
+			relation editor: user
+			permission view = viewer + editor
+		}
+	`, nil, require)
+
+	// Remove the editor relation and add a new definition.
+	compiled, err := compiler.Compile(compiler.InputSchema{
+		Source: input.Source("schema"),
+		SchemaString: `
+			definition user {}
+
+			definition team {}
+
+			definition document {
+				relation viewer: user
+				permission view = viewer
+			}
+		`,
+	}, compiler.AllowUnprefixedObjectType())
+	require.NoError(err)
+
+	validated, err := ValidateSchemaChanges(context.Background(), compiled, false)
+	require.NoError(err)
+
+	updatedRevision, err := ds.ReadWriteTx(context.Background(), func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
+		_, err := ApplySchemaChanges(ctx, rwt, validated)
+		return err
+	})
+	require.NoError(err)
+
+	schemaDiff, err := DiffSchemaAtRevisions(context.Background(), ds, existingRevision, updatedRevision)
+	require.NoError(err)
+	require.Empty(schemaDiff.CaveatDiffs)
+	require.Len(schemaDiff.NamespaceDiffs, 2)
+
+	require.Equal([]nsdiff.Delta{{Type: nsdiff.NamespaceAdded}}, schemaDiff.NamespaceDiffs["team"].Deltas())
+	require.ElementsMatch([]nsdiff.Delta{
+		{Type: nsdiff.RemovedRelation, RelationName: "editor"},
+		{Type: nsdiff.ChangedPermissionImpl, RelationName: "view"},
+	}, schemaDiff.NamespaceDiffs["document"].Deltas())
+
+	// Diffing a revision against itself should find no changes.
+	schemaDiff, err = DiffSchemaAtRevisions(context.Background(), ds, updatedRevision, updatedRevision)
+	require.NoError(err)
+	require.Empty(schemaDiff.NamespaceDiffs)
+	require.Empty(schemaDiff.CaveatDiffs)
+
+	// A revision outside of the GC window cannot be diffed.
+	_, err = DiffSchemaAtRevisions(context.Background(), ds, revisions.NewForTime(time.Now().Add(-2*time.Hour)), updatedRevision)
+	require.ErrorAs(err, &datastore.ErrInvalidRevision{})
+}
@@ -147,7 +147,7 @@ func convertCheckTrace(ctx context.Context, caveatContext map[string]any, ct *di
 		}
 
 		slices.SortFunc(subProblems, func(a, b *v1.CheckDebugTrace) int {
-			return cmp.Compare(tuple.StringObjectRef(a.Resource), tuple.StringObjectRef(a.Resource))
+			return cmp.Compare(tuple.StringObjectRef(a.Resource), tuple.StringObjectRef(b.Resource))
 		})
 
 		return &v1.CheckDebugTrace{
@@ -77,6 +77,28 @@ func expectMissingContext(context ...string) rda {
 	}
 }
 
+func expectSortedSubProblems() rda {
+	var checkSorted func(req *require.Assertions, checkTrace *v1.CheckDebugTrace)
+	checkSorted = func(req *require.Assertions, checkTrace *v1.CheckDebugTrace) {
+		subProblems := checkTrace.GetSubProblems()
+		if subProblems == nil {
+			return
+		}
+
+		resources := make([]string, 0, len(subProblems.Traces))
+		for _, sp := range subProblems.Traces {
+			resources = append(resources, tuple.StringObjectRef(sp.Resource))
+			checkSorted(req, sp)
+		}
+
+		req.True(sort.StringsAreSorted(resources), "expected subproblems to be sorted by resource, found: %v", resources)
+	}
+
+	return func(req *require.Assertions, debugInfo *v1.DebugInformation) {
+		checkSorted(req, debugInfo.Check)
+	}
+}
+
 func findFrame(checkTrace *v1.CheckDebugTrace, resourceType string, permissionName string) *v1.CheckDebugTrace {
 	if checkTrace.Resource.ObjectType == resourceType && checkTrace.Permission == permissionName {
 		return checkTrace
@@ -303,6 +325,40 @@ func TestCheckPermissionWithDebug(t *testing.T) {
 				},
 			},
 		},
+		{
+			"sorted subproblems",
+			`definition user {}
+
+			 definition folder {
+				relation viewer: user
+			 }
+
+			 definition document {
+				relation parent: folder
+				relation viewer: user
+				permission view = parent->viewer + viewer
+			 }
+			`,
+			[]*core.RelationTuple{
+				tuple.MustParse("document:first#parent@folder:somefolder"),
+				tuple.MustParse("document:first#viewer@user:tom"),
+				tuple.MustParse("folder:somefolder#viewer@user:sarah"),
+			},
+			[]debugCheckInfo{
+				{
+					"benny as nothing",
+					debugCheckRequest{
+						obj("document", "first"),
+						"view",
+						sub("user", "benny", ""),
+						nil,
+					},
+					v1.CheckPermissionResponse_PERMISSIONSHIP_NO_PERMISSION,
+					2,
+					[]rda{expectDebugFrames("viewer"), expectSortedSubProblems()},
+				},
+			},
+		},
 		{
 			"ip address caveat",
 			`definition user {}
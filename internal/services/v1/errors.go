@@ -236,39 +236,77 @@ func NewCouldNotTransactionallyDeleteErr(filter *v1.RelationshipFilter, limit ui
 
 // GRPCStatus implements retrieving the gRPC status for the error.
 func (err ErrCouldNotTransactionallyDelete) GRPCStatus() *status.Status {
+	metadata := filterMetadata(err.filter)
+	metadata["limit"] = strconv.Itoa(int(err.limit))
+
+	return spiceerrors.WithCodeAndDetails(
+		err,
+		codes.InvalidArgument,
+		spiceerrors.ForReason(
+			v1.ErrorReason_ERROR_REASON_TOO_MANY_RELATIONSHIPS_FOR_TRANSACTIONAL_DELETE,
+			metadata,
+		),
+	)
+}
+
+// ErrBroadDeleteFilter indicates that a deletion was rejected because its filter was too broad.
+type ErrBroadDeleteFilter struct {
+	error
+	filter *v1.RelationshipFilter
+}
+
+// NewBroadDeleteFilterErr constructs a new broad delete filter error.
+func NewBroadDeleteFilterErr(filter *v1.RelationshipFilter) ErrBroadDeleteFilter {
+	return ErrBroadDeleteFilter{
+		error: fmt.Errorf(
+			"refusing to delete all relationships for resource type `%s`: the filter must specify a resource ID or a subject filter, or the request must specify a limit",
+			filter.ResourceType,
+		),
+		filter: filter,
+	}
+}
+
+// GRPCStatus implements retrieving the gRPC status for the error.
+func (err ErrBroadDeleteFilter) GRPCStatus() *status.Status {
+	return spiceerrors.WithCodeAndDetails(
+		err,
+		codes.FailedPrecondition,
+		// None of the reasons defined by the v1 API describe a rejected filter: the closest,
+		// ERROR_REASON_TOO_MANY_RELATIONSHIPS_FOR_TRANSACTIONAL_DELETE, is returned only once a
+		// limit has been exceeded, and adding a reason requires a change to the API definitions.
+		spiceerrors.ForReason(
+			v1.ErrorReason_ERROR_REASON_UNSPECIFIED,
+			filterMetadata(err.filter),
+		),
+	)
+}
+
+func filterMetadata(filter *v1.RelationshipFilter) map[string]string {
 	metadata := map[string]string{
-		"limit":                strconv.Itoa(int(err.limit)),
-		"filter_resource_type": err.filter.ResourceType,
+		"filter_resource_type": filter.ResourceType,
 	}
 
-	if err.filter.OptionalResourceId != "" {
-		metadata["filter_resource_id"] = err.filter.OptionalResourceId
+	if filter.OptionalResourceId != "" {
+		metadata["filter_resource_id"] = filter.OptionalResourceId
 	}
 
-	if err.filter.OptionalRelation != "" {
-		metadata["filter_relation"] = err.filter.OptionalRelation
+	if filter.OptionalRelation != "" {
+		metadata["filter_relation"] = filter.OptionalRelation
 	}
 
-	if err.filter.OptionalSubjectFilter != nil {
-		metadata["filter_subject_type"] = err.filter.OptionalSubjectFilter.SubjectType
+	if filter.OptionalSubjectFilter != nil {
+		metadata["filter_subject_type"] = filter.OptionalSubjectFilter.SubjectType
 
-		if err.filter.OptionalSubjectFilter.OptionalSubjectId != "" {
-			metadata["filter_subject_id"] = err.filter.OptionalSubjectFilter.OptionalSubjectId
+		if filter.OptionalSubjectFilter.OptionalSubjectId != "" {
+			metadata["filter_subject_id"] = filter.OptionalSubjectFilter.OptionalSubjectId
 		}
 
-		if err.filter.OptionalSubjectFilter.OptionalRelation != nil {
-			metadata["filter_subject_relation"] = err.filter.OptionalSubjectFilter.OptionalRelation.Relation
+		if filter.OptionalSubjectFilter.OptionalRelation != nil {
+			metadata["filter_subject_relation"] = filter.OptionalSubjectFilter.OptionalRelation.Relation
 		}
 	}
 
-	return spiceerrors.WithCodeAndDetails(
-		err,
-		codes.InvalidArgument,
-		spiceerrors.ForReason(
-			v1.ErrorReason_ERROR_REASON_TOO_MANY_RELATIONSHIPS_FOR_TRANSACTIONAL_DELETE,
-			metadata,
-		),
-	)
+	return metadata
 }
 
 // ErrInvalidCursor indicates that an invalid cursor was found.
@@ -207,6 +207,9 @@ func (es *experimentalServer) rewriteError(ctx context.Context, err error) error
 	return shared.RewriteError(ctx, err, nil)
 }
 
+// BulkImportRelationships loads every relationship received over the stream within a single
+// read-write transaction: the whole import is committed at one revision, or not at all if any
+// relationship fails validation or the stream is interrupted.
 func (es *experimentalServer) BulkImportRelationships(stream v1.ExperimentalService_BulkImportRelationshipsServer) error {
 	ds := datastoremw.MustFromContext(stream.Context())
 
@@ -620,7 +623,7 @@ func queryForEach(
 		hadTuples = true
 	}
 	if iter.Err() != nil {
-		return nil, err
+		return nil, iter.Err()
 	}
 
 	var cur *core.RelationTuple
@@ -105,6 +105,52 @@ func TestBulkImportRelationships(t *testing.T) {
 	}
 }
 
+func TestBulkImportRelationshipsIsAtomic(t *testing.T) {
+	require := require.New(t)
+
+	conn, cleanup, _, _ := testserver.NewTestServer(require, 0, memdb.DisableGC, true, tf.StandardDatastoreWithSchema)
+	client := v1.NewExperimentalServiceClient(conn)
+	t.Cleanup(cleanup)
+
+	ctx := context.Background()
+
+	writer, err := client.BulkImportRelationships(ctx)
+	require.NoError(err)
+
+	err = writer.Send(&v1.BulkImportRelationshipsRequest{
+		Relationships: []*v1.Relationship{
+			rel(tf.DocumentNS.Name, "first", "viewer", tf.UserNS.Name, "tom", ""),
+			rel(tf.DocumentNS.Name, "second", "viewer", tf.UserNS.Name, "tom", ""),
+		},
+	})
+	require.NoError(err)
+
+	err = writer.Send(&v1.BulkImportRelationshipsRequest{
+		Relationships: []*v1.Relationship{
+			rel(tf.DocumentNS.Name, "third", "unknownrelation", tf.UserNS.Name, "tom", ""),
+		},
+	})
+	require.NoError(err)
+
+	_, err = writer.CloseAndRecv()
+	require.Error(err)
+
+	// None of the relationships, including those in the valid batch, should have been written.
+	readerClient := v1.NewPermissionsServiceClient(conn)
+	stream, err := readerClient.ReadRelationships(ctx, &v1.ReadRelationshipsRequest{
+		RelationshipFilter: &v1.RelationshipFilter{
+			ResourceType: tf.DocumentNS.Name,
+		},
+		Consistency: &v1.Consistency{
+			Requirement: &v1.Consistency_FullyConsistent{FullyConsistent: true},
+		},
+	})
+	require.NoError(err)
+
+	_, err = stream.Recv()
+	require.ErrorIs(err, io.EOF)
+}
+
 func constBatch(size int) func() int {
 	return func() int {
 		return size
@@ -429,6 +475,39 @@ func TestBulkCheckPermission(t *testing.T) {
 			},
 			expectedDispatchCount: 17,
 		},
+		{
+			name: "interleaved failures and repeats preserve request order",
+			requests: []string{
+				`document:companyplan#view@user:eng_lead[test:{"secret": "1234"}]`,
+				"fake:fake#fake@fake:fake",
+				`document:masterplan#view@user:eng_lead[test:{"secret": "1234"}]`,
+				"fake:fake#fake@fake:fake",
+				`document:companyplan#view@user:eng_lead[test:{"secret": "1234"}]`,
+			},
+			response: []bulkCheckTest{
+				{
+					req:  `document:companyplan#view@user:eng_lead[test:{"secret": "1234"}]`,
+					resp: v1.CheckPermissionResponse_PERMISSIONSHIP_NO_PERMISSION,
+				},
+				{
+					req: "fake:fake#fake@fake:fake",
+					err: namespace.NewNamespaceNotFoundErr("fake"),
+				},
+				{
+					req:  `document:masterplan#view@user:eng_lead[test:{"secret": "1234"}]`,
+					resp: v1.CheckPermissionResponse_PERMISSIONSHIP_HAS_PERMISSION,
+				},
+				{
+					req: "fake:fake#fake@fake:fake",
+					err: namespace.NewNamespaceNotFoundErr("fake"),
+				},
+				{
+					req:  `document:companyplan#view@user:eng_lead[test:{"secret": "1234"}]`,
+					resp: v1.CheckPermissionResponse_PERMISSIONSHIP_NO_PERMISSION,
+				},
+			},
+			expectedDispatchCount: 18,
+		},
 	}
 
 	for _, tt := range testCases {
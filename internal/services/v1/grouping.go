@@ -7,6 +7,7 @@ import (
 
 	"github.com/authzed/spicedb/internal/graph/computed"
 	"github.com/authzed/spicedb/pkg/datastore"
+	"github.com/authzed/spicedb/pkg/genutil/mapz"
 	core "github.com/authzed/spicedb/pkg/proto/core/v1"
 )
 
@@ -15,6 +16,7 @@ var MaxBulkCheckDispatchChunkSize = datastore.FilterMaximumIDCount
 type groupedCheckParameters struct {
 	params      *computed.CheckParameters
 	resourceIDs []string
+	seenIDs     *mapz.Set[string]
 }
 
 type groupingParameters struct {
@@ -24,7 +26,9 @@ type groupingParameters struct {
 }
 
 // groupItems takes a slice of BulkCheckPermissionRequestItem and groups them based
-// on using the same permission, subject type, subject id, and caveat.
+// on using the same permission, subject type, subject id, and caveat. Identical items
+// are collapsed into a single resource ID within their group, so that they are only
+// dispatched once.
 func groupItems(ctx context.Context, params groupingParameters, items []*v1.BulkCheckPermissionRequestItem) (map[string]*groupedCheckParameters, error) {
 	res := make(map[string]*groupedCheckParameters)
 
@@ -43,8 +47,9 @@ func groupItems(ctx context.Context, params groupingParameters, items []*v1.Bulk
 			res[hash] = &groupedCheckParameters{
 				params:      checkParametersFromBulkCheckPermissionRequestItem(item, params, caveatContext),
 				resourceIDs: []string{item.Resource.ObjectId},
+				seenIDs:     mapz.NewSet(item.Resource.ObjectId),
 			}
-		} else {
+		} else if res[hash].seenIDs.Add(item.Resource.ObjectId) {
 			res[hash].resourceIDs = append(res[hash].resourceIDs, item.Resource.ObjectId)
 		}
 	}
@@ -139,6 +139,23 @@ func TestGroupItems(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "identical items are deduplicated",
+			requests: []string{
+				"document:1#view@user:1",
+				"document:2#view@user:1",
+				"document:1#view@user:1",
+				"document:1#view@user:1",
+			},
+			groupings: []expectedGroupedRequest{
+				{
+					resourceType: "document",
+					resourceRel:  "view",
+					subject:      "user:1",
+					resourceIDs:  []string{"1", "2"},
+				},
+			},
+		},
 		{
 			name: "different caveat context cannot be grouped",
 			requests: []string{
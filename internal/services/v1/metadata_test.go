@@ -203,6 +203,11 @@ func checkServiceMethods[T any](
 			dispatchCount, err := responsemeta.GetIntResponseTrailerMetadata(trailer, responsemeta.DispatchedOperationsCount)
 			require.NoError(t, err)
 			require.Greater(t, dispatchCount, 0)
+
+			cachedCount, err := responsemeta.GetIntResponseTrailerMetadata(trailer, responsemeta.CachedOperationsCount)
+			require.NoError(t, err)
+			require.GreaterOrEqual(t, cachedCount, 0)
+			require.LessOrEqual(t, cachedCount, dispatchCount)
 		})
 	}
 }
@@ -8,6 +8,8 @@ import (
 	"github.com/authzed/authzed-go/pkg/responsemeta"
 	v1 "github.com/authzed/authzed-go/proto/authzed/api/v1"
 	"github.com/jzelinskie/stringz"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
@@ -31,6 +33,21 @@ import (
 	"github.com/authzed/spicedb/pkg/tuple"
 )
 
+var checkPermissionCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "spicedb",
+	Subsystem: "v1",
+	Name:      "check_permission_results_total",
+	Help:      "The results of the CheckPermission calls",
+}, []string{"object_type", "permission", "permissionship"})
+
+var checkPermissionDepthHistogram = promauto.NewHistogram(prometheus.HistogramOpts{
+	Namespace: "spicedb",
+	Subsystem: "v1",
+	Name:      "check_permission_depth_required",
+	Help:      "The dispatch depth required to compute the CheckPermission calls",
+	Buckets:   []float64{1, 2, 3, 5, 10, 15, 25, 50},
+})
+
 func (ps *permissionServer) rewriteError(ctx context.Context, err error) error {
 	return shared.RewriteError(ctx, err, &shared.ConfigForErrors{
 		MaximumAPIDepth: ps.config.MaximumAPIDepth,
@@ -120,6 +137,7 @@ func (ps *permissionServer) CheckPermission(ctx context.Context, req *v1.CheckPe
 	}
 
 	permissionship, partialCaveat := checkResultToAPITypes(cr)
+	ps.recordCheckPermissionMetrics(req, permissionship, metadata)
 
 	return &v1.CheckPermissionResponse{
 		CheckedAt:         checkedAt,
@@ -128,6 +146,22 @@ func (ps *permissionServer) CheckPermission(ctx context.Context, req *v1.CheckPe
 	}, nil
 }
 
+// recordCheckPermissionMetrics records the result of a CheckPermission call. The object type
+// label is only set if enabled in the config, as it can be of high cardinality.
+func (ps *permissionServer) recordCheckPermissionMetrics(req *v1.CheckPermissionRequest, permissionship v1.CheckPermissionResponse_Permissionship, metadata *dispatch.ResponseMeta) {
+	objectType := ""
+	if ps.config.CheckMetricsObjectTypeLabelEnabled {
+		objectType = req.Resource.ObjectType
+	}
+
+	checkPermissionCounter.WithLabelValues(
+		objectType,
+		req.Permission,
+		v1.CheckPermissionResponse_Permissionship_name[int32(permissionship)],
+	).Inc()
+	checkPermissionDepthHistogram.Observe(float64(metadata.GetDepthRequired()))
+}
+
 func checkResultToAPITypes(cr *dispatch.ResourceCheckResult) (v1.CheckPermissionResponse_Permissionship, *v1.PartialCaveatInfo) {
 	var partialCaveat *v1.PartialCaveatInfo
 	permissionship := v1.CheckPermissionResponse_PERMISSIONSHIP_NO_PERMISSION
@@ -502,6 +536,10 @@ func (ps *permissionServer) LookupSubjects(req *v1.LookupSubjectsRequest, resp v
 		}
 
 		for _, foundSubject := range foundSubjects.FoundSubjects {
+			if req.WildcardOption == v1.LookupSubjectsRequest_WILDCARD_OPTION_EXCLUDE_WILDCARDS && foundSubject.SubjectId == tuple.PublicWildcard {
+				continue
+			}
+
 			excludedSubjectIDs := make([]string, 0, len(foundSubject.ExcludedSubjects))
 			for _, excludedSubject := range foundSubject.ExcludedSubjects {
 				excludedSubjectIDs = append(excludedSubjectIDs, excludedSubject.SubjectId)
@@ -16,15 +16,19 @@ import (
 	"github.com/authzed/authzed-go/pkg/responsemeta"
 	v1 "github.com/authzed/authzed-go/proto/authzed/api/v1"
 	"github.com/authzed/grpcutil"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/types/known/structpb"
 
 	"github.com/authzed/spicedb/internal/datastore/memdb"
+	"github.com/authzed/spicedb/internal/datastore/revisions"
 	v1svc "github.com/authzed/spicedb/internal/services/v1"
 	tf "github.com/authzed/spicedb/internal/testfixtures"
 	"github.com/authzed/spicedb/internal/testserver"
@@ -314,6 +318,93 @@ func TestCheckPermissions(t *testing.T) {
 	}
 }
 
+func TestCheckPermissionUnknownSchemaErrorDetails(t *testing.T) {
+	req := require.New(t)
+	conn, cleanup, _, revision := testserver.NewTestServer(req, 0, memdb.DisableGC, true,
+		func(ds datastore.Datastore, assertions *require.Assertions) (datastore.Datastore, datastore.Revision) {
+			return tf.DatastoreFromSchemaAndTestRelationships(ds, `
+				definition user {}
+
+				definition document {
+					relation viewer: user
+					relation editor: user
+					permission view = viewer + editor
+				}
+			`, []*core.RelationTuple{tuple.MustParse("document:firstdoc#viewer@user:tom")}, assertions)
+		})
+	client := v1.NewPermissionsServiceClient(conn)
+	t.Cleanup(cleanup)
+
+	testCases := []struct {
+		name             string
+		resource         *v1.ObjectReference
+		permission       string
+		subject          *v1.SubjectReference
+		expectedReason   v1.ErrorReason
+		expectedMetadata map[string]string
+	}{
+		{
+			"unknown permission",
+			obj("document", "firstdoc"),
+			"viwe",
+			sub("user", "tom", ""),
+			v1.ErrorReason_ERROR_REASON_UNKNOWN_RELATION_OR_PERMISSION,
+			map[string]string{
+				"definition_name":                        "document",
+				"relation_or_permission_name":            "viwe",
+				"available_relation_or_permission_names": "editor,view,viewer",
+			},
+		},
+		{
+			"unknown subject relation",
+			obj("document", "firstdoc"),
+			"view",
+			sub("user", "tom", "member"),
+			v1.ErrorReason_ERROR_REASON_UNKNOWN_RELATION_OR_PERMISSION,
+			map[string]string{
+				"definition_name":             "user",
+				"relation_or_permission_name": "member",
+			},
+		},
+		{
+			"unknown resource definition",
+			obj("docment", "firstdoc"),
+			"view",
+			sub("user", "tom", ""),
+			v1.ErrorReason_ERROR_REASON_UNKNOWN_DEFINITION,
+			map[string]string{
+				"definition_name": "docment",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := client.CheckPermission(context.Background(), &v1.CheckPermissionRequest{
+				Consistency: &v1.Consistency{
+					Requirement: &v1.Consistency_AtLeastAsFresh{
+						AtLeastAsFresh: zedtoken.MustNewFromRevision(revision),
+					},
+				},
+				Resource:   tc.resource,
+				Permission: tc.permission,
+				Subject:    tc.subject,
+			})
+			grpcutil.RequireStatus(t, codes.FailedPrecondition, err)
+
+			withStatus, ok := status.FromError(err)
+			require.True(t, ok)
+			require.Len(t, withStatus.Details(), 1)
+
+			info, ok := withStatus.Details()[0].(*errdetails.ErrorInfo)
+			require.True(t, ok)
+			require.Equal(t, v1.ErrorReason_name[int32(tc.expectedReason)], info.Reason)
+			require.Equal(t, tc.expectedMetadata, info.Metadata)
+		})
+	}
+}
+
 func TestCheckPermissionWithDebugInfo(t *testing.T) {
 	require := require.New(t)
 	conn, cleanup, _, revision := testserver.NewTestServer(require, testTimedeltas[0], memdb.DisableGC, true, tf.StandardDatastoreWithData)
@@ -1426,6 +1517,163 @@ func TestLookupSubjectsWithCaveatedWildcards(t *testing.T) {
 	require.True(t, found)
 }
 
+func TestLookupSubjectsWithExclusions(t *testing.T) {
+	req := require.New(t)
+	conn, cleanup, _, revision := testserver.NewTestServer(req, testTimedeltas[0], memdb.DisableGC, true,
+		func(ds datastore.Datastore, require *require.Assertions) (datastore.Datastore, datastore.Revision) {
+			return tf.DatastoreFromSchemaAndTestRelationships(ds, `
+				definition user {}
+
+				definition group {
+					relation member: user | group#member
+				}
+
+				definition document {
+					relation viewer: user | group#member
+					relation banned: user | group#member
+					permission view = viewer - banned
+				}
+			`, []*core.RelationTuple{
+				tuple.MustParse("document:first#viewer@user:amy"),
+				tuple.MustParse("document:first#viewer@group:eng#member"),
+				tuple.MustParse("group:eng#member@user:tom"),
+				tuple.MustParse("group:eng#member@user:sarah"),
+				tuple.MustParse("group:eng#member@group:interns#member"),
+				tuple.MustParse("group:interns#member@user:fred"),
+				tuple.MustParse("group:interns#member@user:george"),
+				tuple.MustParse("document:first#banned@user:sarah"),
+				tuple.MustParse("document:first#banned@group:contractors#member"),
+				tuple.MustParse("group:contractors#member@user:fred"),
+			}, require)
+		})
+
+	client := v1.NewPermissionsServiceClient(conn)
+	t.Cleanup(cleanup)
+
+	testCases := []struct {
+		permission         string
+		expectedSubjectIds []string
+	}{
+		{"viewer", []string{"amy", "fred", "george", "sarah", "tom"}},
+		{"banned", []string{"fred", "sarah"}},
+		{"view", []string{"amy", "george", "tom"}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.permission, func(t *testing.T) {
+			lookupClient, err := client.LookupSubjects(context.Background(), &v1.LookupSubjectsRequest{
+				Consistency: &v1.Consistency{
+					Requirement: &v1.Consistency_AtLeastAsFresh{
+						AtLeastAsFresh: zedtoken.MustNewFromRevision(revision),
+					},
+				},
+				Resource:          obj("document", "first"),
+				Permission:        tc.permission,
+				SubjectObjectType: "user",
+			})
+			require.NoError(t, err)
+
+			var resolvedSubjectIds []string
+			for {
+				resp, err := lookupClient.Recv()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+
+				require.NoError(t, err)
+				require.Empty(t, resp.ExcludedSubjects)
+				require.Equal(t, v1.LookupPermissionship_LOOKUP_PERMISSIONSHIP_HAS_PERMISSION, resp.Subject.Permissionship)
+
+				resolvedSubjectIds = append(resolvedSubjectIds, resp.Subject.SubjectObjectId)
+			}
+
+			slices.Sort(resolvedSubjectIds)
+			require.Equal(t, tc.expectedSubjectIds, resolvedSubjectIds)
+		})
+	}
+}
+
+func TestLookupSubjectsWithWildcards(t *testing.T) {
+	req := require.New(t)
+	conn, cleanup, _, revision := testserver.NewTestServer(req, testTimedeltas[0], memdb.DisableGC, true,
+		func(ds datastore.Datastore, require *require.Assertions) (datastore.Datastore, datastore.Revision) {
+			return tf.DatastoreFromSchemaAndTestRelationships(ds, `
+				definition user {}
+
+				definition document {
+					relation viewer: user | user:*
+					relation banned: user
+					permission view = viewer - banned
+				}
+			`, []*core.RelationTuple{
+				tuple.MustParse("document:public#viewer@user:*"),
+				tuple.MustParse("document:public#viewer@user:tom"),
+				tuple.MustParse("document:publicexcept#viewer@user:*"),
+				tuple.MustParse("document:publicexcept#viewer@user:sarah"),
+				tuple.MustParse("document:publicexcept#banned@user:sarah"),
+				tuple.MustParse("document:publicexcept#banned@user:fred"),
+				tuple.MustParse("document:private#viewer@user:tom"),
+			}, require)
+		})
+
+	client := v1.NewPermissionsServiceClient(conn)
+	t.Cleanup(cleanup)
+
+	testCases := []struct {
+		resourceID     string
+		wildcardOption v1.LookupSubjectsRequest_WildcardOption
+		// expectedSubjects maps each expected subject ID to the IDs excluded from it.
+		expectedSubjects map[string][]string
+	}{
+		{"public", v1.LookupSubjectsRequest_WILDCARD_OPTION_UNSPECIFIED, map[string][]string{"*": nil, "tom": nil}},
+		{"publicexcept", v1.LookupSubjectsRequest_WILDCARD_OPTION_UNSPECIFIED, map[string][]string{"*": {"fred", "sarah"}}},
+		{"private", v1.LookupSubjectsRequest_WILDCARD_OPTION_UNSPECIFIED, map[string][]string{"tom": nil}},
+		{"public", v1.LookupSubjectsRequest_WILDCARD_OPTION_INCLUDE_WILDCARDS, map[string][]string{"*": nil, "tom": nil}},
+		{"public", v1.LookupSubjectsRequest_WILDCARD_OPTION_EXCLUDE_WILDCARDS, map[string][]string{"tom": nil}},
+		{"publicexcept", v1.LookupSubjectsRequest_WILDCARD_OPTION_EXCLUDE_WILDCARDS, map[string][]string{}},
+		{"private", v1.LookupSubjectsRequest_WILDCARD_OPTION_EXCLUDE_WILDCARDS, map[string][]string{"tom": nil}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprintf("%s/%s", tc.resourceID, tc.wildcardOption), func(t *testing.T) {
+			lookupClient, err := client.LookupSubjects(context.Background(), &v1.LookupSubjectsRequest{
+				Consistency: &v1.Consistency{
+					Requirement: &v1.Consistency_AtLeastAsFresh{
+						AtLeastAsFresh: zedtoken.MustNewFromRevision(revision),
+					},
+				},
+				Resource:          obj("document", tc.resourceID),
+				Permission:        "view",
+				SubjectObjectType: "user",
+				WildcardOption:    tc.wildcardOption,
+			})
+			require.NoError(t, err)
+
+			resolvedSubjects := map[string][]string{}
+			for {
+				resp, err := lookupClient.Recv()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+
+				require.NoError(t, err)
+
+				var excludedIds []string
+				for _, excluded := range resp.ExcludedSubjects {
+					excludedIds = append(excludedIds, excluded.SubjectObjectId)
+				}
+				slices.Sort(excludedIds)
+
+				resolvedSubjects[resp.Subject.SubjectObjectId] = excludedIds
+			}
+
+			require.Equal(t, tc.expectedSubjects, resolvedSubjects)
+		})
+	}
+}
+
 type expectedSubject struct {
 	subjectID     string
 	isConditional bool
@@ -1512,6 +1760,11 @@ func TestLookupResourcesWithCursors(t *testing.T) {
 			sub("user", "owner", ""),
 			[]string{"masterplan", "companyplan", "ownerplan"},
 		},
+		{
+			"document", "view",
+			sub("user", "villain", ""),
+			nil,
+		},
 	}
 
 	for _, delta := range testTimedeltas {
@@ -1576,6 +1829,11 @@ func TestLookupResourcesWithCursors(t *testing.T) {
 							slices.Sort(resolvedObjectIds)
 
 							require.Equal(tc.expectedObjectIds, resolvedObjectIds)
+
+							// An empty result set should terminate without ever handing out a cursor.
+							if len(tc.expectedObjectIds) == 0 {
+								require.Nil(currentCursor)
+							}
 						})
 					}
 				})
@@ -1631,3 +1889,1175 @@ func TestLookupResourcesDeduplication(t *testing.T) {
 
 	require.Equal(t, []string{"first"}, foundObjectIds.AsSlice())
 }
+
+func TestLookupResourcesWithWildcards(t *testing.T) {
+	testCases := []struct {
+		permission        string
+		subject           *v1.SubjectReference
+		expectedObjectIds []string
+	}{
+		{
+			"view",
+			sub("user", "somebody", ""),
+			[]string{"public", "alsopublic"},
+		},
+		{
+			"view",
+			sub("user", "tom", ""),
+			[]string{"public", "alsopublic", "private"},
+		},
+		{
+			"view",
+			sub("user", "sarah", ""),
+			[]string{"public", "alsopublic"},
+		},
+		{
+			"edit",
+			sub("user", "somebody", ""),
+			nil,
+		},
+		{
+			"edit",
+			sub("user", "tom", ""),
+			[]string{"private"},
+		},
+		{
+			"view",
+			sub("team", "someteam", ""),
+			nil,
+		},
+	}
+
+	for _, delta := range testTimedeltas {
+		delta := delta
+		t.Run(fmt.Sprintf("fuzz%d", delta/time.Millisecond), func(t *testing.T) {
+			for _, tc := range testCases {
+				tc := tc
+				t.Run(fmt.Sprintf("%s from %s:%s", tc.permission, tc.subject.Object.ObjectType, tc.subject.Object.ObjectId), func(t *testing.T) {
+					conn, cleanup, _, revision := testserver.NewTestServer(require.New(t), delta, memdb.DisableGC, true,
+						func(ds datastore.Datastore, require *require.Assertions) (datastore.Datastore, datastore.Revision) {
+							return tf.DatastoreFromSchemaAndTestRelationships(ds, `
+								definition user {}
+
+								definition team {}
+
+								definition document {
+									relation viewer: user | user:*
+									relation editor: user
+									permission view = viewer + editor
+									permission edit = editor
+								}
+							`, []*core.RelationTuple{
+								tuple.MustParse("document:public#viewer@user:*"),
+								tuple.MustParse("document:public#viewer@user:tom"),
+								tuple.MustParse("document:alsopublic#viewer@user:*"),
+								tuple.MustParse("document:alsopublic#editor@user:sarah"),
+								tuple.MustParse("document:private#editor@user:tom"),
+							}, require)
+						})
+
+					client := v1.NewPermissionsServiceClient(conn)
+					t.Cleanup(cleanup)
+
+					lookupClient, err := client.LookupResources(context.Background(), &v1.LookupResourcesRequest{
+						ResourceObjectType: "document",
+						Permission:         tc.permission,
+						Subject:            tc.subject,
+						Consistency: &v1.Consistency{
+							Requirement: &v1.Consistency_AtLeastAsFresh{
+								AtLeastAsFresh: zedtoken.MustNewFromRevision(revision),
+							},
+						},
+					})
+					require.NoError(t, err)
+
+					var resolvedObjectIds []string
+					for {
+						resp, err := lookupClient.Recv()
+						if errors.Is(err, io.EOF) {
+							break
+						}
+
+						require.NoError(t, err)
+						require.NotContains(t, resolvedObjectIds, resp.ResourceObjectId, "found duplicate resource")
+
+						resolvedObjectIds = append(resolvedObjectIds, resp.ResourceObjectId)
+					}
+
+					slices.Sort(tc.expectedObjectIds)
+					slices.Sort(resolvedObjectIds)
+
+					require.Equal(t, tc.expectedObjectIds, resolvedObjectIds)
+				})
+			}
+		})
+	}
+}
+
+func TestLookupResourcesCursorPinsRevision(t *testing.T) {
+	req := require.New(t)
+	conn, cleanup, _, revision := testserver.NewTestServer(req, 0, memdb.DisableGC, true,
+		func(ds datastore.Datastore, require *require.Assertions) (datastore.Datastore, datastore.Revision) {
+			return tf.DatastoreFromSchemaAndTestRelationships(ds, `
+				definition user {}
+
+				definition document {
+					relation viewer: user
+					permission view = viewer
+				}
+			`, []*core.RelationTuple{
+				tuple.MustParse("document:first#viewer@user:tom"),
+				tuple.MustParse("document:second#viewer@user:tom"),
+				tuple.MustParse("document:third#viewer@user:tom"),
+			}, require)
+		})
+
+	client := v1.NewPermissionsServiceClient(conn)
+	t.Cleanup(cleanup)
+
+	lookup := func(subject *v1.SubjectReference, limit uint32, cursor *v1.Cursor) ([]string, *v1.Cursor, error) {
+		lookupClient, err := client.LookupResources(context.Background(), &v1.LookupResourcesRequest{
+			ResourceObjectType: "document",
+			Permission:         "view",
+			Subject:            subject,
+			Consistency: &v1.Consistency{
+				Requirement: &v1.Consistency_AtLeastAsFresh{
+					AtLeastAsFresh: zedtoken.MustNewFromRevision(revision),
+				},
+			},
+			OptionalLimit:  limit,
+			OptionalCursor: cursor,
+		})
+		req.NoError(err)
+
+		var found []string
+		for {
+			resp, err := lookupClient.Recv()
+			if errors.Is(err, io.EOF) {
+				return found, cursor, nil
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+
+			found = append(found, resp.ResourceObjectId)
+			cursor = resp.AfterResultCursor
+		}
+	}
+
+	firstPage, cursor, err := lookup(sub("user", "tom", ""), 2, nil)
+	req.NoError(err)
+	req.Len(firstPage, 2)
+	req.NotNil(cursor)
+
+	// Grant access to another document after the cursor was issued.
+	_, err = client.WriteRelationships(context.Background(), &v1.WriteRelationshipsRequest{
+		Updates: []*v1.RelationshipUpdate{
+			tuple.UpdateToRelationshipUpdate(tuple.Create(tuple.MustParse("document:fourth#viewer@user:tom"))),
+		},
+	})
+	req.NoError(err)
+
+	// Resuming with the cursor must read at the cursor's revision, and thus not see the new grant.
+	secondPage, _, err := lookup(sub("user", "tom", ""), 2, cursor)
+	req.NoError(err)
+
+	// NOTE: results may be repeated across pages, so they are collected into a set.
+	allFound := mapz.NewSet(firstPage...)
+	allFound.Extend(secondPage)
+
+	resolvedObjectIds := allFound.AsSlice()
+	slices.Sort(resolvedObjectIds)
+	req.Equal([]string{"first", "second", "third"}, resolvedObjectIds)
+
+	// A cursor cannot be used for a call with different arguments.
+	_, _, err = lookup(sub("user", "sarah", ""), 2, cursor)
+	req.ErrorContains(err, "does not have the same arguments")
+	grpcutil.RequireStatus(t, codes.InvalidArgument, err)
+}
+
+func TestPermissionResponsesIncludeRevision(t *testing.T) {
+	req := require.New(t)
+	conn, cleanup, _, revision := testserver.NewTestServer(req, testTimedeltas[0], memdb.DisableGC, true, tf.StandardDatastoreWithData)
+	client := v1.NewPermissionsServiceClient(conn)
+	t.Cleanup(cleanup)
+
+	ctx := context.Background()
+	expectedToken := zedtoken.MustNewFromRevision(revision)
+	atExactSnapshot := &v1.Consistency{
+		Requirement: &v1.Consistency_AtExactSnapshot{
+			AtExactSnapshot: expectedToken,
+		},
+	}
+
+	checkResp, err := client.CheckPermission(ctx, &v1.CheckPermissionRequest{
+		Consistency: atExactSnapshot,
+		Resource:    obj("document", "masterplan"),
+		Permission:  "view",
+		Subject:     sub("user", "eng_lead", ""),
+	})
+	req.NoError(err)
+	req.Equal(expectedToken.Token, checkResp.CheckedAt.Token)
+
+	expandResp, err := client.ExpandPermissionTree(ctx, &v1.ExpandPermissionTreeRequest{
+		Consistency: atExactSnapshot,
+		Resource:    obj("document", "masterplan"),
+		Permission:  "view",
+	})
+	req.NoError(err)
+	req.Equal(expectedToken.Token, expandResp.ExpandedAt.Token)
+
+	lookupClient, err := client.LookupResources(ctx, &v1.LookupResourcesRequest{
+		Consistency:        atExactSnapshot,
+		ResourceObjectType: "document",
+		Permission:         "view",
+		Subject:            sub("user", "eng_lead", ""),
+	})
+	req.NoError(err)
+
+	lookupResp, err := lookupClient.Recv()
+	req.NoError(err)
+	req.Equal(expectedToken.Token, lookupResp.LookedUpAt.Token)
+
+	// A call made with minimize latency must return a token that can be fed back into a subsequent call.
+	checkResp, err = client.CheckPermission(ctx, &v1.CheckPermissionRequest{
+		Consistency: &v1.Consistency{
+			Requirement: &v1.Consistency_MinimizeLatency{MinimizeLatency: true},
+		},
+		Resource:   obj("document", "masterplan"),
+		Permission: "view",
+		Subject:    sub("user", "eng_lead", ""),
+	})
+	req.NoError(err)
+	req.NotNil(checkResp.CheckedAt)
+
+	checkResp, err = client.CheckPermission(ctx, &v1.CheckPermissionRequest{
+		Consistency: &v1.Consistency{
+			Requirement: &v1.Consistency_AtLeastAsFresh{
+				AtLeastAsFresh: checkResp.CheckedAt,
+			},
+		},
+		Resource:   obj("document", "masterplan"),
+		Permission: "view",
+		Subject:    sub("user", "eng_lead", ""),
+	})
+	req.NoError(err)
+	req.Equal(v1.CheckPermissionResponse_PERMISSIONSHIP_HAS_PERMISSION, checkResp.Permissionship)
+}
+
+func TestCheckPermissionFullyConsistentSeesLatestWrite(t *testing.T) {
+	for _, delta := range []time.Duration{0, 1 * time.Second, 1 * time.Hour} {
+		delta := delta
+		t.Run(fmt.Sprintf("fuzz%d", delta/time.Millisecond), func(t *testing.T) {
+			require := require.New(t)
+			conn, cleanup, _, _ := testserver.NewTestServer(require, delta, memdb.DisableGC, true, tf.StandardDatastoreWithData)
+			client := v1.NewPermissionsServiceClient(conn)
+			t.Cleanup(cleanup)
+
+			ctx := context.Background()
+			_, err := client.WriteRelationships(ctx, &v1.WriteRelationshipsRequest{
+				Updates: []*v1.RelationshipUpdate{
+					tuple.UpdateToRelationshipUpdate(tuple.Touch(tuple.MustParse("document:newplan#viewer@user:villain"))),
+				},
+			})
+			require.NoError(err)
+
+			// Even with a large quantization window, a fully consistent check must observe the write.
+			checkResp, err := client.CheckPermission(ctx, &v1.CheckPermissionRequest{
+				Consistency: &v1.Consistency{
+					Requirement: &v1.Consistency_FullyConsistent{FullyConsistent: true},
+				},
+				Resource:   obj("document", "newplan"),
+				Permission: "view",
+				Subject:    sub("user", "villain", ""),
+			})
+			require.NoError(err)
+			require.Equal(v1.CheckPermissionResponse_PERMISSIONSHIP_HAS_PERMISSION, checkResp.Permissionship)
+		})
+	}
+}
+
+func cyclicFolderDatastore(ds datastore.Datastore, assertions *require.Assertions) (datastore.Datastore, datastore.Revision) {
+	return tf.DatastoreFromSchemaAndTestRelationships(
+		ds,
+		`definition user {}
+
+		definition folder {
+			relation parent: folder
+			relation viewer: user
+			permission view = viewer + parent->view
+		}`,
+		[]*core.RelationTuple{
+			tuple.MustParse("folder:first#parent@folder:second"),
+			tuple.MustParse("folder:second#parent@folder:first"),
+		},
+		assertions,
+	)
+}
+
+func TestCheckPermissionOnRecursiveDataReturnsResourceExhausted(t *testing.T) {
+	conn, cleanup, _, revision := testserver.NewTestServer(require.New(t), 0, memdb.DisableGC, true, cyclicFolderDatastore)
+	client := v1.NewPermissionsServiceClient(conn)
+	t.Cleanup(func() {
+		goleak.VerifyNone(t, goleak.IgnoreCurrent())
+	})
+	t.Cleanup(cleanup)
+
+	_, err := client.CheckPermission(context.Background(), &v1.CheckPermissionRequest{
+		Consistency: &v1.Consistency{
+			Requirement: &v1.Consistency_AtLeastAsFresh{
+				AtLeastAsFresh: zedtoken.MustNewFromRevision(revision),
+			},
+		},
+		Resource:   obj("folder", "first"),
+		Permission: "view",
+		Subject:    sub("user", "someone", ""),
+	})
+	grpcutil.RequireStatus(t, codes.ResourceExhausted, err)
+	require.ErrorContains(t, err, "maximum depth")
+}
+
+func TestExpandPermissionTreeOnRecursiveDataReturnsResourceExhausted(t *testing.T) {
+	conn, cleanup, _, revision := testserver.NewTestServer(require.New(t), 0, memdb.DisableGC, true, cyclicFolderDatastore)
+	client := v1.NewPermissionsServiceClient(conn)
+	t.Cleanup(func() {
+		goleak.VerifyNone(t, goleak.IgnoreCurrent())
+	})
+	t.Cleanup(cleanup)
+
+	_, err := client.ExpandPermissionTree(context.Background(), &v1.ExpandPermissionTreeRequest{
+		Consistency: &v1.Consistency{
+			Requirement: &v1.Consistency_AtLeastAsFresh{
+				AtLeastAsFresh: zedtoken.MustNewFromRevision(revision),
+			},
+		},
+		Resource:   obj("folder", "first"),
+		Permission: "view",
+	})
+	grpcutil.RequireStatus(t, codes.ResourceExhausted, err)
+	require.ErrorContains(t, err, "maximum depth")
+}
+
+func TestCheckPermissionWithIPCaveat(t *testing.T) {
+	req := require.New(t)
+	conn, cleanup, _, revision := testserver.NewTestServer(req, 0, memdb.DisableGC, true,
+		func(ds datastore.Datastore, assertions *require.Assertions) (datastore.Datastore, datastore.Revision) {
+			return tf.DatastoreFromSchemaAndTestRelationships(
+				ds,
+				`definition user {}
+
+				caveat is_allowed_ip(user_ip ipaddress, allowed_cidr string) {
+					user_ip.in_cidr(allowed_cidr)
+				}
+
+				definition document {
+					relation viewer: user with is_allowed_ip
+					permission view = viewer
+				}`,
+				[]*core.RelationTuple{
+					tuple.MustParse(`document:firstdoc#viewer@user:tom[is_allowed_ip:{"allowed_cidr":"10.0.0.0/8"}]`),
+				},
+				assertions,
+			)
+		})
+	client := v1.NewPermissionsServiceClient(conn)
+	t.Cleanup(cleanup)
+
+	testCases := []struct {
+		name                   string
+		context                map[string]any
+		expectedPermissionship v1.CheckPermissionResponse_Permissionship
+		expectedMissingContext []string
+	}{
+		{
+			"ip within the allowed range",
+			map[string]any{"user_ip": "10.1.2.3"},
+			v1.CheckPermissionResponse_PERMISSIONSHIP_HAS_PERMISSION,
+			nil,
+		},
+		{
+			"ip outside the allowed range",
+			map[string]any{"user_ip": "192.168.1.1"},
+			v1.CheckPermissionResponse_PERMISSIONSHIP_NO_PERMISSION,
+			nil,
+		},
+		{
+			"missing ip",
+			nil,
+			v1.CheckPermissionResponse_PERMISSIONSHIP_CONDITIONAL_PERMISSION,
+			[]string{"user_ip"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			request := &v1.CheckPermissionRequest{
+				Consistency: &v1.Consistency{
+					Requirement: &v1.Consistency_AtLeastAsFresh{
+						AtLeastAsFresh: zedtoken.MustNewFromRevision(revision),
+					},
+				},
+				Resource:   obj("document", "firstdoc"),
+				Permission: "view",
+				Subject:    sub("user", "tom", ""),
+			}
+
+			if tc.context != nil {
+				caveatContext, err := structpb.NewStruct(tc.context)
+				require.NoError(t, err)
+				request.Context = caveatContext
+			}
+
+			checkResp, err := client.CheckPermission(context.Background(), request)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedPermissionship, checkResp.Permissionship)
+
+			if tc.expectedMissingContext != nil {
+				require.Equal(t, tc.expectedMissingContext, checkResp.PartialCaveatInfo.MissingRequiredContext)
+			} else {
+				require.Nil(t, checkResp.PartialCaveatInfo)
+			}
+		})
+	}
+}
+
+// Time-limited grants are expressed as caveats over a timestamp supplied by the caller,
+// rather than as an expiration stored on the relationship itself.
+func TestCheckPermissionWithExpiringGrantCaveat(t *testing.T) {
+	req := require.New(t)
+	conn, cleanup, _, revision := testserver.NewTestServer(req, 0, memdb.DisableGC, true,
+		func(ds datastore.Datastore, assertions *require.Assertions) (datastore.Datastore, datastore.Revision) {
+			return tf.DatastoreFromSchemaAndTestRelationships(
+				ds,
+				`definition user {}
+
+				caveat not_expired(now timestamp, expires_at timestamp) {
+					now < expires_at
+				}
+
+				definition document {
+					relation viewer: user with not_expired
+					permission view = viewer
+				}`,
+				[]*core.RelationTuple{
+					tuple.MustParse(`document:expired#viewer@user:tom[not_expired:{"expires_at":"2020-01-01T00:00:00Z"}]`),
+					tuple.MustParse(`document:active#viewer@user:tom[not_expired:{"expires_at":"2999-01-01T00:00:00Z"}]`),
+				},
+				assertions,
+			)
+		})
+	client := v1.NewPermissionsServiceClient(conn)
+	t.Cleanup(cleanup)
+
+	caveatContext, err := structpb.NewStruct(map[string]any{
+		"now": time.Now().UTC().Format(time.RFC3339),
+	})
+	req.NoError(err)
+
+	consistency := &v1.Consistency{
+		Requirement: &v1.Consistency_AtLeastAsFresh{
+			AtLeastAsFresh: zedtoken.MustNewFromRevision(revision),
+		},
+	}
+
+	for resourceID, expected := range map[string]v1.CheckPermissionResponse_Permissionship{
+		"expired": v1.CheckPermissionResponse_PERMISSIONSHIP_NO_PERMISSION,
+		"active":  v1.CheckPermissionResponse_PERMISSIONSHIP_HAS_PERMISSION,
+	} {
+		checkResp, err := client.CheckPermission(context.Background(), &v1.CheckPermissionRequest{
+			Consistency: consistency,
+			Resource:    obj("document", resourceID),
+			Permission:  "view",
+			Subject:     sub("user", "tom", ""),
+			Context:     caveatContext,
+		})
+		req.NoError(err)
+		req.Equal(expected, checkResp.Permissionship, "unexpected permissionship for %s", resourceID)
+	}
+
+	lookupClient, err := client.LookupResources(context.Background(), &v1.LookupResourcesRequest{
+		Consistency:        consistency,
+		ResourceObjectType: "document",
+		Permission:         "view",
+		Subject:            sub("user", "tom", ""),
+		Context:            caveatContext,
+	})
+	req.NoError(err)
+
+	var foundResourceIDs []string
+	for {
+		resp, err := lookupClient.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		req.NoError(err)
+		req.Equal(v1.LookupPermissionship_LOOKUP_PERMISSIONSHIP_HAS_PERMISSION, resp.Permissionship)
+		foundResourceIDs = append(foundResourceIDs, resp.ResourceObjectId)
+	}
+	req.Equal([]string{"active"}, foundResourceIDs)
+}
+
+func TestCheckPermissionWithSubjectRelation(t *testing.T) {
+	req := require.New(t)
+	conn, cleanup, _, revision := testserver.NewTestServer(req, 0, memdb.DisableGC, true,
+		func(ds datastore.Datastore, assertions *require.Assertions) (datastore.Datastore, datastore.Revision) {
+			return tf.DatastoreFromSchemaAndTestRelationships(
+				ds,
+				`definition user {}
+
+				definition group {
+					relation member: user | group#member
+				}
+
+				definition document {
+					relation viewer: user | group#member
+					permission view = viewer
+				}`,
+				[]*core.RelationTuple{
+					tuple.MustParse("document:roadmap#viewer@group:eng#member"),
+					tuple.MustParse("group:eng#member@group:infra#member"),
+					tuple.MustParse("group:infra#member@group:sre#member"),
+					tuple.MustParse("group:sre#member@user:tom"),
+					tuple.MustParse("group:sales#member@user:sarah"),
+				},
+				assertions,
+			)
+		})
+	client := v1.NewPermissionsServiceClient(conn)
+	t.Cleanup(cleanup)
+
+	testCases := []struct {
+		name                   string
+		subject                *v1.SubjectReference
+		expectedPermissionship v1.CheckPermissionResponse_Permissionship
+	}{
+		{
+			"directly granted group",
+			sub("group", "eng", "member"),
+			v1.CheckPermissionResponse_PERMISSIONSHIP_HAS_PERMISSION,
+		},
+		{
+			"nested group",
+			sub("group", "infra", "member"),
+			v1.CheckPermissionResponse_PERMISSIONSHIP_HAS_PERMISSION,
+		},
+		{
+			"doubly nested group",
+			sub("group", "sre", "member"),
+			v1.CheckPermissionResponse_PERMISSIONSHIP_HAS_PERMISSION,
+		},
+		{
+			"user in doubly nested group",
+			sub("user", "tom", ""),
+			v1.CheckPermissionResponse_PERMISSIONSHIP_HAS_PERMISSION,
+		},
+		{
+			"unrelated group",
+			sub("group", "sales", "member"),
+			v1.CheckPermissionResponse_PERMISSIONSHIP_NO_PERMISSION,
+		},
+		{
+			"user in unrelated group",
+			sub("user", "sarah", ""),
+			v1.CheckPermissionResponse_PERMISSIONSHIP_NO_PERMISSION,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			checkResp, err := client.CheckPermission(context.Background(), &v1.CheckPermissionRequest{
+				Consistency: &v1.Consistency{
+					Requirement: &v1.Consistency_AtLeastAsFresh{
+						AtLeastAsFresh: zedtoken.MustNewFromRevision(revision),
+					},
+				},
+				Resource:   obj("document", "roadmap"),
+				Permission: "view",
+				Subject:    tc.subject,
+			})
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedPermissionship, checkResp.Permissionship)
+		})
+	}
+}
+
+func TestLookupResourcesWithSubjectRelation(t *testing.T) {
+	req := require.New(t)
+	conn, cleanup, _, revision := testserver.NewTestServer(req, 0, memdb.DisableGC, true,
+		func(ds datastore.Datastore, assertions *require.Assertions) (datastore.Datastore, datastore.Revision) {
+			return tf.DatastoreFromSchemaAndTestRelationships(
+				ds,
+				`definition user {}
+
+				definition group {
+					relation member: user | group#member
+				}
+
+				definition document {
+					relation viewer: user | group#member
+					permission view = viewer
+				}`,
+				[]*core.RelationTuple{
+					tuple.MustParse("document:roadmap#viewer@group:eng#member"),
+					tuple.MustParse("document:oncall#viewer@group:sre#member"),
+					tuple.MustParse("document:pricing#viewer@group:sales#member"),
+					tuple.MustParse("group:eng#member@group:infra#member"),
+					tuple.MustParse("group:infra#member@group:sre#member"),
+					tuple.MustParse("group:sre#member@user:tom"),
+					tuple.MustParse("group:sales#member@user:sarah"),
+				},
+				assertions,
+			)
+		})
+	client := v1.NewPermissionsServiceClient(conn)
+	t.Cleanup(cleanup)
+
+	testCases := []struct {
+		name              string
+		subject           *v1.SubjectReference
+		expectedObjectIds []string
+	}{
+		{
+			"directly granted group",
+			sub("group", "eng", "member"),
+			[]string{"roadmap"},
+		},
+		{
+			"nested group",
+			sub("group", "infra", "member"),
+			[]string{"roadmap"},
+		},
+		{
+			"doubly nested group",
+			sub("group", "sre", "member"),
+			[]string{"oncall", "roadmap"},
+		},
+		{
+			"user in doubly nested group",
+			sub("user", "tom", ""),
+			[]string{"oncall", "roadmap"},
+		},
+		{
+			"unrelated group",
+			sub("group", "sales", "member"),
+			[]string{"pricing"},
+		},
+		{
+			"unknown group",
+			sub("group", "unknown", "member"),
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			lookupClient, err := client.LookupResources(context.Background(), &v1.LookupResourcesRequest{
+				ResourceObjectType: "document",
+				Permission:         "view",
+				Subject:            tc.subject,
+				Consistency: &v1.Consistency{
+					Requirement: &v1.Consistency_AtLeastAsFresh{
+						AtLeastAsFresh: zedtoken.MustNewFromRevision(revision),
+					},
+				},
+			})
+			require.NoError(t, err)
+
+			var resolvedObjectIds []string
+			for {
+				resp, err := lookupClient.Recv()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+
+				require.NoError(t, err)
+				require.NotContains(t, resolvedObjectIds, resp.ResourceObjectId, "found duplicate resource")
+				resolvedObjectIds = append(resolvedObjectIds, resp.ResourceObjectId)
+			}
+
+			slices.Sort(resolvedObjectIds)
+			require.Equal(t, tc.expectedObjectIds, resolvedObjectIds)
+		})
+	}
+
+	t.Run("unknown subject relation", func(t *testing.T) {
+		lookupClient, err := client.LookupResources(context.Background(), &v1.LookupResourcesRequest{
+			ResourceObjectType: "document",
+			Permission:         "view",
+			Subject:            sub("group", "eng", "unknown"),
+			Consistency: &v1.Consistency{
+				Requirement: &v1.Consistency_AtLeastAsFresh{
+					AtLeastAsFresh: zedtoken.MustNewFromRevision(revision),
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = lookupClient.Recv()
+		grpcutil.RequireStatus(t, codes.FailedPrecondition, err)
+	})
+}
+
+func TestLookupResourcesLimitCapsLargeResultSets(t *testing.T) {
+	const documentCount = 1000
+	const limit = 25
+
+	relationships := make([]*core.RelationTuple, 0, documentCount)
+	for i := 0; i < documentCount; i++ {
+		relationships = append(relationships, tuple.MustParse(fmt.Sprintf("document:doc-%04d#viewer@user:tom", i)))
+	}
+
+	req := require.New(t)
+	conn, cleanup, _, revision := testserver.NewTestServer(req, 0, memdb.DisableGC, true,
+		func(ds datastore.Datastore, assertions *require.Assertions) (datastore.Datastore, datastore.Revision) {
+			return tf.DatastoreFromSchemaAndTestRelationships(ds, `
+				definition user {}
+
+				definition document {
+					relation viewer: user
+					permission view = viewer
+				}
+			`, relationships, assertions)
+		})
+	client := v1.NewPermissionsServiceClient(conn)
+	t.Cleanup(cleanup)
+
+	lookup := func(limit uint32, cursor *v1.Cursor) ([]string, *v1.Cursor) {
+		lookupClient, err := client.LookupResources(context.Background(), &v1.LookupResourcesRequest{
+			ResourceObjectType: "document",
+			Permission:         "view",
+			Subject:            sub("user", "tom", ""),
+			Consistency: &v1.Consistency{
+				Requirement: &v1.Consistency_AtLeastAsFresh{
+					AtLeastAsFresh: zedtoken.MustNewFromRevision(revision),
+				},
+			},
+			OptionalLimit:  limit,
+			OptionalCursor: cursor,
+		})
+		req.NoError(err)
+
+		var resourceIds []string
+		var lastCursor *v1.Cursor
+		for {
+			resp, err := lookupClient.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			req.NoError(err)
+			resourceIds = append(resourceIds, resp.ResourceObjectId)
+			lastCursor = resp.AfterResultCursor
+		}
+		return resourceIds, lastCursor
+	}
+
+	// The stream must stop once the limit is reached, handing back a cursor from which the
+	// remaining results can be fetched.
+	firstPage, cursor := lookup(limit, nil)
+	req.Len(firstPage, limit)
+	req.NotNil(cursor)
+
+	found := mapz.NewSet[string](firstPage...)
+	for cursor != nil {
+		var page []string
+		page, cursor = lookup(limit, cursor)
+		req.LessOrEqual(len(page), limit)
+
+		for _, resourceID := range page {
+			req.True(found.Add(resourceID), "found duplicate resource %s", resourceID)
+		}
+	}
+	req.Equal(documentCount, found.Len())
+}
+
+func TestCheckPermissionAtExactSnapshotIsReproducible(t *testing.T) {
+	req := require.New(t)
+	conn, cleanup, _, revision := testserver.NewTestServer(req, 0, memdb.DisableGC, true, tf.StandardDatastoreWithData)
+	client := v1.NewPermissionsServiceClient(conn)
+	t.Cleanup(cleanup)
+
+	ctx := context.Background()
+	checkAt := func(token *v1.ZedToken) v1.CheckPermissionResponse_Permissionship {
+		resp, err := client.CheckPermission(ctx, &v1.CheckPermissionRequest{
+			Consistency: &v1.Consistency{
+				Requirement: &v1.Consistency_AtExactSnapshot{AtExactSnapshot: token},
+			},
+			Resource:   obj("document", "masterplan"),
+			Permission: "view",
+			Subject:    sub("user", "eng_lead", ""),
+		})
+		req.NoError(err)
+		req.Equal(token.Token, resp.CheckedAt.Token)
+		return resp.Permissionship
+	}
+
+	beforeDelete := zedtoken.MustNewFromRevision(revision)
+	req.Equal(v1.CheckPermissionResponse_PERMISSIONSHIP_HAS_PERMISSION, checkAt(beforeDelete))
+
+	deleteResp, err := client.WriteRelationships(ctx, &v1.WriteRelationshipsRequest{
+		Updates: []*v1.RelationshipUpdate{{
+			Operation:    v1.RelationshipUpdate_OPERATION_DELETE,
+			Relationship: rel("document", "masterplan", "viewer", "user", "eng_lead", ""),
+		}},
+	})
+	req.NoError(err)
+
+	// The check at the later revision reflects the deletion, while the check at the
+	// earlier revision is still evaluated against the data as it was then.
+	req.Equal(v1.CheckPermissionResponse_PERMISSIONSHIP_NO_PERMISSION, checkAt(deleteResp.WrittenAt))
+	req.Equal(v1.CheckPermissionResponse_PERMISSIONSHIP_HAS_PERMISSION, checkAt(beforeDelete))
+}
+
+func TestCheckPermissionAtExactSnapshotOutsideValidRange(t *testing.T) {
+	req := require.New(t)
+	conn, cleanup, _, _ := testserver.NewTestServer(req, 0, 10*time.Minute, true, tf.StandardDatastoreWithData)
+	client := v1.NewPermissionsServiceClient(conn)
+	t.Cleanup(cleanup)
+
+	for _, tc := range []struct {
+		name     string
+		revision datastore.Revision
+	}{
+		{"future revision", revisions.NewForTime(time.Now().Add(1 * time.Hour))},
+		{"garbage collected revision", revisions.NewForTime(time.Now().Add(-1 * time.Hour))},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := client.CheckPermission(context.Background(), &v1.CheckPermissionRequest{
+				Consistency: &v1.Consistency{
+					Requirement: &v1.Consistency_AtExactSnapshot{
+						AtExactSnapshot: zedtoken.MustNewFromRevision(tc.revision),
+					},
+				},
+				Resource:   obj("document", "masterplan"),
+				Permission: "view",
+				Subject:    sub("user", "eng_lead", ""),
+			})
+			grpcutil.RequireStatus(t, codes.OutOfRange, err)
+			require.ErrorContains(t, err, "invalid revision")
+		})
+	}
+}
+
+func TestCheckPermissionRecordsResultMetrics(t *testing.T) {
+	req := require.New(t)
+	conn, cleanup, _, _ := testserver.NewTestServer(req, 0, memdb.DisableGC, true, tf.StandardDatastoreWithData)
+	client := v1.NewPermissionsServiceClient(conn)
+	t.Cleanup(cleanup)
+
+	resultCount := func(permissionship v1.CheckPermissionResponse_Permissionship) float64 {
+		families, err := prometheus.DefaultGatherer.Gather()
+		req.NoError(err)
+
+		for _, family := range families {
+			if family.GetName() != "spicedb_v1_check_permission_results_total" {
+				continue
+			}
+
+			for _, metric := range family.GetMetric() {
+				labels := make(map[string]string, len(metric.GetLabel()))
+				for _, label := range metric.GetLabel() {
+					labels[label.GetName()] = label.GetValue()
+				}
+
+				// The object type label is disabled by default.
+				if labels["object_type"] == "" && labels["permission"] == "view" &&
+					labels["permissionship"] == permissionship.String() {
+					return metric.GetCounter().GetValue()
+				}
+			}
+		}
+		return 0
+	}
+
+	hasBefore := resultCount(v1.CheckPermissionResponse_PERMISSIONSHIP_HAS_PERMISSION)
+	noBefore := resultCount(v1.CheckPermissionResponse_PERMISSIONSHIP_NO_PERMISSION)
+
+	for _, subject := range []string{"eng_lead", "eng_lead", "villain"} {
+		_, err := client.CheckPermission(context.Background(), &v1.CheckPermissionRequest{
+			Consistency: &v1.Consistency{
+				Requirement: &v1.Consistency_FullyConsistent{FullyConsistent: true},
+			},
+			Resource:   obj("document", "masterplan"),
+			Permission: "view",
+			Subject:    sub("user", subject, ""),
+		})
+		req.NoError(err)
+	}
+
+	req.Equal(hasBefore+2, resultCount(v1.CheckPermissionResponse_PERMISSIONSHIP_HAS_PERMISSION))
+	req.Equal(noBefore+1, resultCount(v1.CheckPermissionResponse_PERMISSIONSHIP_NO_PERMISSION))
+}
+
+func TestNestedIntersectionAndExclusion(t *testing.T) {
+	req := require.New(t)
+	conn, cleanup, _, revision := testserver.NewTestServer(req, 0, memdb.DisableGC, true,
+		func(ds datastore.Datastore, assertions *require.Assertions) (datastore.Datastore, datastore.Revision) {
+			return tf.DatastoreFromSchemaAndTestRelationships(
+				ds,
+				`definition user {}
+
+				definition organization {
+					relation member: user
+				}
+
+				definition document {
+					relation org: organization
+					relation admin: user
+					relation viewer: user
+					relation suspended: user
+					permission member_of_org = org->member
+					permission exclusion_first = (viewer - suspended) & member_of_org
+					permission intersection_first = (viewer & member_of_org) - suspended
+					permission view = admin + intersection_first
+				}`,
+				[]*core.RelationTuple{
+					tuple.MustParse("document:doc1#org@organization:acme"),
+					tuple.MustParse("document:doc2#org@organization:other"),
+					tuple.MustParse("organization:acme#member@user:alice"),
+					tuple.MustParse("organization:acme#member@user:bob"),
+					tuple.MustParse("organization:acme#member@user:dave"),
+					tuple.MustParse("document:doc1#viewer@user:alice"),
+					tuple.MustParse("document:doc2#viewer@user:alice"),
+					tuple.MustParse("document:doc1#viewer@user:bob"),
+					tuple.MustParse("document:doc1#suspended@user:bob"),
+					tuple.MustParse("document:doc1#viewer@user:carol"),
+					tuple.MustParse("document:doc2#admin@user:erin"),
+					tuple.MustParse("document:doc2#suspended@user:erin"),
+				},
+				assertions,
+			)
+		})
+	client := v1.NewPermissionsServiceClient(conn)
+	t.Cleanup(cleanup)
+
+	consistency := &v1.Consistency{
+		Requirement: &v1.Consistency_AtLeastAsFresh{
+			AtLeastAsFresh: zedtoken.MustNewFromRevision(revision),
+		},
+	}
+
+	// Only alice is a viewer, an organization member and not suspended, and only on doc1;
+	// erin is suspended, but is granted view on doc2 through admin.
+	for _, permission := range []string{"exclusion_first", "intersection_first", "view"} {
+		for _, userID := range []string{"alice", "bob", "carol", "dave", "erin"} {
+			var expectedObjectIds []string
+			switch {
+			case userID == "alice":
+				expectedObjectIds = []string{"doc1"}
+			case userID == "erin" && permission == "view":
+				expectedObjectIds = []string{"doc2"}
+			}
+
+			permission := permission
+			userID := userID
+			t.Run(fmt.Sprintf("%s@%s", permission, userID), func(t *testing.T) {
+				for _, documentID := range []string{"doc1", "doc2"} {
+					resp, err := client.CheckPermission(context.Background(), &v1.CheckPermissionRequest{
+						Consistency: consistency,
+						Resource:    obj("document", documentID),
+						Permission:  permission,
+						Subject:     sub("user", userID, ""),
+					})
+					require.NoError(t, err)
+
+					expected := v1.CheckPermissionResponse_PERMISSIONSHIP_NO_PERMISSION
+					if slices.Contains(expectedObjectIds, documentID) {
+						expected = v1.CheckPermissionResponse_PERMISSIONSHIP_HAS_PERMISSION
+					}
+					require.Equal(t, expected, resp.Permissionship, "unexpected permissionship on %s", documentID)
+				}
+
+				lookupClient, err := client.LookupResources(context.Background(), &v1.LookupResourcesRequest{
+					ResourceObjectType: "document",
+					Permission:         permission,
+					Subject:            sub("user", userID, ""),
+					Consistency:        consistency,
+				})
+				require.NoError(t, err)
+
+				var resolvedObjectIds []string
+				for {
+					resp, err := lookupClient.Recv()
+					if errors.Is(err, io.EOF) {
+						break
+					}
+
+					require.NoError(t, err)
+					require.Equal(t, v1.LookupPermissionship_LOOKUP_PERMISSIONSHIP_HAS_PERMISSION, resp.Permissionship)
+					resolvedObjectIds = append(resolvedObjectIds, resp.ResourceObjectId)
+				}
+
+				slices.Sort(resolvedObjectIds)
+				require.Equal(t, expectedObjectIds, resolvedObjectIds)
+			})
+		}
+	}
+}
+
+func TestArrowToPermissionOnParent(t *testing.T) {
+	req := require.New(t)
+	conn, cleanup, _, revision := testserver.NewTestServer(req, 0, memdb.DisableGC, true,
+		func(ds datastore.Datastore, assertions *require.Assertions) (datastore.Datastore, datastore.Revision) {
+			return tf.DatastoreFromSchemaAndTestRelationships(
+				ds,
+				`definition user {}
+
+				definition folder {
+					relation owner: user
+					permission admin = owner
+				}
+
+				definition document {
+					relation parent: folder
+					relation editor: user
+					permission edit = editor + parent->admin
+				}`,
+				[]*core.RelationTuple{
+					tuple.MustParse("folder:shared#owner@user:tom"),
+					tuple.MustParse("document:plan#parent@folder:shared"),
+					tuple.MustParse("document:budget#parent@folder:shared"),
+					tuple.MustParse("document:notes#editor@user:tom"),
+					tuple.MustParse("document:other#editor@user:sarah"),
+				},
+				assertions,
+			)
+		})
+	client := v1.NewPermissionsServiceClient(conn)
+	t.Cleanup(cleanup)
+
+	consistency := &v1.Consistency{
+		Requirement: &v1.Consistency_AtLeastAsFresh{
+			AtLeastAsFresh: zedtoken.MustNewFromRevision(revision),
+		},
+	}
+
+	resp, err := client.CheckPermission(context.Background(), &v1.CheckPermissionRequest{
+		Consistency: consistency,
+		Resource:    obj("document", "plan"),
+		Permission:  "edit",
+		Subject:     sub("user", "tom", ""),
+	})
+	req.NoError(err)
+	req.Equal(v1.CheckPermissionResponse_PERMISSIONSHIP_HAS_PERMISSION, resp.Permissionship)
+
+	// LookupResources walks the arrow in reverse, from the folder to its documents.
+	lookupClient, err := client.LookupResources(context.Background(), &v1.LookupResourcesRequest{
+		ResourceObjectType: "document",
+		Permission:         "edit",
+		Subject:            sub("user", "tom", ""),
+		Consistency:        consistency,
+	})
+	req.NoError(err)
+
+	var resolvedObjectIds []string
+	for {
+		resp, err := lookupClient.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		req.NoError(err)
+		resolvedObjectIds = append(resolvedObjectIds, resp.ResourceObjectId)
+	}
+
+	slices.Sort(resolvedObjectIds)
+	req.Equal([]string{"budget", "notes", "plan"}, resolvedObjectIds)
+}
+
+func TestPrefixedDefinitionsIsolateTenants(t *testing.T) {
+	req := require.New(t)
+	conn, cleanup, _, revision := testserver.NewTestServer(req, 0, memdb.DisableGC, true,
+		func(ds datastore.Datastore, assertions *require.Assertions) (datastore.Datastore, datastore.Revision) {
+			return tf.DatastoreFromSchemaAndTestRelationships(
+				ds,
+				`definition tenanta/user {}
+
+				definition tenanta/document {
+					relation viewer: tenanta/user
+					permission view = viewer
+				}
+
+				definition tenantb/user {}
+
+				definition tenantb/document {
+					relation viewer: tenantb/user
+					permission view = viewer
+				}`,
+				[]*core.RelationTuple{
+					tuple.MustParse("tenanta/document:doc#viewer@tenanta/user:alice"),
+					tuple.MustParse("tenantb/document:doc#viewer@tenantb/user:bob"),
+				},
+				assertions,
+			)
+		})
+	client := v1.NewPermissionsServiceClient(conn)
+	t.Cleanup(cleanup)
+
+	consistency := &v1.Consistency{
+		Requirement: &v1.Consistency_AtLeastAsFresh{
+			AtLeastAsFresh: zedtoken.MustNewFromRevision(revision),
+		},
+	}
+
+	for _, tc := range []struct {
+		resource *v1.ObjectReference
+		subject  *v1.SubjectReference
+		expected v1.CheckPermissionResponse_Permissionship
+	}{
+		{obj("tenanta/document", "doc"), sub("tenanta/user", "alice", ""), v1.CheckPermissionResponse_PERMISSIONSHIP_HAS_PERMISSION},
+		{obj("tenanta/document", "doc"), sub("tenanta/user", "bob", ""), v1.CheckPermissionResponse_PERMISSIONSHIP_NO_PERMISSION},
+		{obj("tenantb/document", "doc"), sub("tenantb/user", "bob", ""), v1.CheckPermissionResponse_PERMISSIONSHIP_HAS_PERMISSION},
+		{obj("tenantb/document", "doc"), sub("tenantb/user", "alice", ""), v1.CheckPermissionResponse_PERMISSIONSHIP_NO_PERMISSION},
+		{obj("tenantb/document", "doc"), sub("tenanta/user", "alice", ""), v1.CheckPermissionResponse_PERMISSIONSHIP_NO_PERMISSION},
+	} {
+		resp, err := client.CheckPermission(context.Background(), &v1.CheckPermissionRequest{
+			Consistency: consistency,
+			Resource:    tc.resource,
+			Permission:  "view",
+			Subject:     tc.subject,
+		})
+		req.NoError(err)
+		req.Equal(tc.expected, resp.Permissionship, "%s:%s for %s:%s", tc.resource.ObjectType, tc.resource.ObjectId, tc.subject.Object.ObjectType, tc.subject.Object.ObjectId)
+	}
+
+	lookupResources := func(resourceType string, subject *v1.SubjectReference) []string {
+		lookupClient, err := client.LookupResources(context.Background(), &v1.LookupResourcesRequest{
+			ResourceObjectType: resourceType,
+			Permission:         "view",
+			Subject:            subject,
+			Consistency:        consistency,
+		})
+		req.NoError(err)
+
+		var resolvedObjectIds []string
+		for {
+			resp, err := lookupClient.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			req.NoError(err)
+			resolvedObjectIds = append(resolvedObjectIds, resp.ResourceObjectId)
+		}
+		return resolvedObjectIds
+	}
+
+	req.Equal([]string{"doc"}, lookupResources("tenanta/document", sub("tenanta/user", "alice", "")))
+	req.Empty(lookupResources("tenantb/document", sub("tenantb/user", "alice", "")))
+	req.Empty(lookupResources("tenantb/document", sub("tenanta/user", "alice", "")))
+
+	// A relationship crossing tenants is rejected by the schema.
+	_, err := client.WriteRelationships(context.Background(), &v1.WriteRelationshipsRequest{
+		Updates: []*v1.RelationshipUpdate{{
+			Operation:    v1.RelationshipUpdate_OPERATION_TOUCH,
+			Relationship: rel("tenanta/document", "doc", "viewer", "tenantb/user", "bob", ""),
+		}},
+	})
+	grpcutil.RequireStatus(t, codes.InvalidArgument, err)
+}
@@ -28,7 +28,7 @@ func checkPreconditions(
 
 		first := iter.Next()
 		if first == nil && iter.Err() != nil {
-			return fmt.Errorf("error reading relationships from iterator: %w", err)
+			return fmt.Errorf("error reading relationships from iterator: %w", iter.Err())
 		}
 		iter.Close()
 
@@ -22,6 +22,16 @@ var companyPlanFolder = &v1.RelationshipFilter{
 	},
 }
 
+var companyPlanMissingFolder = &v1.RelationshipFilter{
+	ResourceType:       "document",
+	OptionalResourceId: "companyplan",
+	OptionalRelation:   "parent",
+	OptionalSubjectFilter: &v1.SubjectFilter{
+		SubjectType:       "folder",
+		OptionalSubjectId: "missing",
+	},
+}
+
 func TestPreconditions(t *testing.T) {
 	require := require.New(t)
 	uninitialized, err := memdb.NewMemdbDatastore(0, 0, memdb.DisableGC)
@@ -43,6 +53,26 @@ func TestPreconditions(t *testing.T) {
 				Filter:    companyPlanFolder,
 			},
 		}))
+		require.NoError(checkPreconditions(ctx, rwt, []*v1.Precondition{
+			{
+				Operation: v1.Precondition_OPERATION_MUST_MATCH,
+				Filter:    companyPlanFolder,
+			},
+			{
+				Operation: v1.Precondition_OPERATION_MUST_NOT_MATCH,
+				Filter:    companyPlanMissingFolder,
+			},
+		}))
+		require.Error(checkPreconditions(ctx, rwt, []*v1.Precondition{
+			{
+				Operation: v1.Precondition_OPERATION_MUST_MATCH,
+				Filter:    companyPlanFolder,
+			},
+			{
+				Operation: v1.Precondition_OPERATION_MUST_MATCH,
+				Filter:    companyPlanMissingFolder,
+			},
+		}))
 		return nil
 	})
 	require.NoError(err)
@@ -69,6 +69,14 @@ type PermissionsServerConfig struct {
 	// MaxDatastoreReadPageSize defines the maximum number of relationships loaded from the
 	// datastore in one query.
 	MaxDatastoreReadPageSize uint64
+
+	// CheckMetricsObjectTypeLabelEnabled defines whether the CheckPermission metrics are
+	// labeled by the object type of the resource being checked.
+	CheckMetricsObjectTypeLabelEnabled bool
+
+	// DisallowBroadDeletes defines whether DeleteRelationships calls without a limit are
+	// rejected when their filter specifies neither a resource ID nor a subject filter.
+	DisallowBroadDeletes bool
 }
 
 // NewPermissionsServer creates a PermissionsServiceServer instance.
@@ -84,6 +92,9 @@ func NewPermissionsServer(
 		MaxCaveatContextSize:       defaultIfZero(config.MaxCaveatContextSize, 4096),
 		MaxRelationshipContextSize: defaultIfZero(config.MaxRelationshipContextSize, 25_000),
 		MaxDatastoreReadPageSize:   defaultIfZero(config.MaxDatastoreReadPageSize, 1_000),
+		DisallowBroadDeletes:       config.DisallowBroadDeletes,
+
+		CheckMetricsObjectTypeLabelEnabled: config.CheckMetricsObjectTypeLabelEnabled,
 	}
 
 	return &permissionServer{
@@ -343,6 +354,10 @@ func (ps *permissionServer) DeleteRelationships(ctx context.Context, req *v1.Del
 		)
 	}
 
+	if ps.config.DisallowBroadDeletes && req.OptionalLimit == 0 && isBroadDeleteFilter(req.RelationshipFilter) {
+		return nil, ps.rewriteError(ctx, NewBroadDeleteFilterErr(req.RelationshipFilter))
+	}
+
 	ds := datastoremw.MustFromContext(ctx)
 	deletionProgress := v1.DeleteRelationshipsResponse_DELETION_PROGRESS_COMPLETE
 
@@ -409,3 +424,9 @@ func (ps *permissionServer) DeleteRelationships(ctx context.Context, req *v1.Del
 		DeletionProgress: deletionProgress,
 	}, nil
 }
+
+// isBroadDeleteFilter returns whether the filter selects every relationship of a resource
+// type or relation, rather than those of specific resources or subjects.
+func isBroadDeleteFilter(filter *v1.RelationshipFilter) bool {
+	return filter.OptionalResourceId == "" && filter.OptionalSubjectFilter == nil
+}
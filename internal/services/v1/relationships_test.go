@@ -108,6 +108,68 @@ func TestReadRelationships(t *testing.T) {
 				"document:masterplan#parent@folder:plans": {},
 			},
 		},
+		{
+			"subject type only",
+			&v1.RelationshipFilter{
+				ResourceType: tf.FolderNS.Name,
+				OptionalSubjectFilter: &v1.SubjectFilter{
+					SubjectType: "folder",
+				},
+			},
+			codes.OK,
+			map[string]struct{}{
+				"folder:strategy#parent@folder:company":        {},
+				"folder:company#viewer@folder:auditors#viewer": {},
+			},
+		},
+		{
+			"subject type and relation",
+			&v1.RelationshipFilter{
+				ResourceType: tf.FolderNS.Name,
+				OptionalSubjectFilter: &v1.SubjectFilter{
+					SubjectType: "folder",
+					OptionalRelation: &v1.SubjectFilter_RelationFilter{
+						Relation: "viewer",
+					},
+				},
+			},
+			codes.OK,
+			map[string]struct{}{
+				"folder:company#viewer@folder:auditors#viewer": {},
+			},
+		},
+		{
+			"subject type and ellipsis relation",
+			&v1.RelationshipFilter{
+				ResourceType: tf.FolderNS.Name,
+				OptionalSubjectFilter: &v1.SubjectFilter{
+					SubjectType:      "folder",
+					OptionalRelation: &v1.SubjectFilter_RelationFilter{},
+				},
+			},
+			codes.OK,
+			map[string]struct{}{
+				"folder:strategy#parent@folder:company": {},
+			},
+		},
+		{
+			"subject type, id and relation",
+			&v1.RelationshipFilter{
+				ResourceType:     tf.FolderNS.Name,
+				OptionalRelation: "viewer",
+				OptionalSubjectFilter: &v1.SubjectFilter{
+					SubjectType:       "folder",
+					OptionalSubjectId: "auditors",
+					OptionalRelation: &v1.SubjectFilter_RelationFilter{
+						Relation: "viewer",
+					},
+				},
+			},
+			codes.OK,
+			map[string]struct{}{
+				"folder:company#viewer@folder:auditors#viewer": {},
+			},
+		},
 		{
 			"bad namespace",
 			&v1.RelationshipFilter{ResourceType: ""},
@@ -385,6 +447,181 @@ func TestWriteRelationships(t *testing.T) {
 	}
 }
 
+func TestWriteRelationshipsWithMustNotMatchPrecondition(t *testing.T) {
+	require := require.New(t)
+
+	conn, cleanup, _, _ := testserver.NewTestServer(require, 0, memdb.DisableGC, true, tf.StandardDatastoreWithData)
+	client := v1.NewPermissionsServiceClient(conn)
+	t.Cleanup(cleanup)
+
+	toWrite := tuple.MustParse("document:totallynew#parent@folder:plans")
+	other := tuple.MustParse("document:totallynew#viewer@user:tom")
+
+	writeIfMissing := func() (*v1.WriteRelationshipsResponse, error) {
+		return client.WriteRelationships(context.Background(), &v1.WriteRelationshipsRequest{
+			Updates: []*v1.RelationshipUpdate{
+				{
+					Operation:    v1.RelationshipUpdate_OPERATION_TOUCH,
+					Relationship: tuple.MustToRelationship(toWrite),
+				},
+				{
+					Operation:    v1.RelationshipUpdate_OPERATION_TOUCH,
+					Relationship: tuple.MustToRelationship(other),
+				},
+			},
+			OptionalPreconditions: []*v1.Precondition{{
+				Operation: v1.Precondition_OPERATION_MUST_NOT_MATCH,
+				Filter:    tuple.MustToFilter(toWrite),
+			}},
+		})
+	}
+
+	// The first write succeeds, as the relationship does not yet exist.
+	resp, err := writeIfMissing()
+	require.NoError(err)
+	require.NotNil(resp.WrittenAt)
+
+	// Remove the second relationship so that we can verify the failed write below is not applied.
+	deleted, err := client.WriteRelationships(context.Background(), &v1.WriteRelationshipsRequest{
+		Updates: []*v1.RelationshipUpdate{{
+			Operation:    v1.RelationshipUpdate_OPERATION_DELETE,
+			Relationship: tuple.MustToRelationship(other),
+		}},
+	})
+	require.NoError(err)
+
+	// The second write fails, as the relationship now exists.
+	resp, err = writeIfMissing()
+	require.Nil(resp)
+	grpcutil.RequireStatus(t, codes.FailedPrecondition, err)
+	spiceerrors.RequireReason(t, v1.ErrorReason_ERROR_REASON_WRITE_OR_DELETE_PRECONDITION_FAILURE, err,
+		"precondition_operation",
+		"precondition_relation",
+		"precondition_resource_id",
+		"precondition_resource_type",
+		"precondition_subject_id",
+		"precondition_subject_relation",
+		"precondition_subject_type",
+	)
+
+	// Ensure no part of the failed write was applied.
+	stream, err := client.ReadRelationships(context.Background(), &v1.ReadRelationshipsRequest{
+		Consistency: &v1.Consistency{
+			Requirement: &v1.Consistency_AtLeastAsFresh{AtLeastAsFresh: deleted.WrittenAt},
+		},
+		RelationshipFilter: tuple.MustToFilter(other),
+	})
+	require.NoError(err)
+	_, err = stream.Recv()
+	require.ErrorIs(err, io.EOF)
+}
+
+func TestWriteRelationshipsOperationSemantics(t *testing.T) {
+	require := require.New(t)
+
+	conn, cleanup, _, _ := testserver.NewTestServer(require, 0, memdb.DisableGC, true, tf.StandardDatastoreWithData)
+	client := v1.NewPermissionsServiceClient(conn)
+	t.Cleanup(cleanup)
+
+	write := func(operation v1.RelationshipUpdate_Operation, rels ...string) (*v1.WriteRelationshipsResponse, error) {
+		updates := make([]*v1.RelationshipUpdate, 0, len(rels))
+		for _, rel := range rels {
+			updates = append(updates, &v1.RelationshipUpdate{
+				Operation:    operation,
+				Relationship: tuple.MustToRelationship(tuple.MustParse(rel)),
+			})
+		}
+		return client.WriteRelationships(context.Background(), &v1.WriteRelationshipsRequest{Updates: updates})
+	}
+
+	// CREATE of an existing relationship fails.
+	_, err := write(v1.RelationshipUpdate_OPERATION_CREATE, "document:totallynew#viewer@user:tom")
+	require.NoError(err)
+
+	_, err = write(v1.RelationshipUpdate_OPERATION_CREATE, "document:totallynew#viewer@user:tom")
+	grpcutil.RequireStatus(t, codes.AlreadyExists, err)
+
+	// TOUCH of an existing relationship is idempotent.
+	_, err = write(v1.RelationshipUpdate_OPERATION_TOUCH, "document:totallynew#viewer@user:sarah")
+	require.NoError(err)
+
+	_, err = write(v1.RelationshipUpdate_OPERATION_TOUCH, "document:totallynew#viewer@user:sarah")
+	require.NoError(err)
+
+	// All three operations can be mixed in a single call, applied at one revision.
+	resp, err := client.WriteRelationships(context.Background(), &v1.WriteRelationshipsRequest{
+		Updates: []*v1.RelationshipUpdate{
+			{
+				Operation:    v1.RelationshipUpdate_OPERATION_CREATE,
+				Relationship: tuple.MustToRelationship(tuple.MustParse("document:totallynew#viewer@user:fred")),
+			},
+			{
+				Operation:    v1.RelationshipUpdate_OPERATION_TOUCH,
+				Relationship: tuple.MustToRelationship(tuple.MustParse("document:totallynew#viewer@user:sarah")),
+			},
+			{
+				Operation:    v1.RelationshipUpdate_OPERATION_DELETE,
+				Relationship: tuple.MustToRelationship(tuple.MustParse("document:totallynew#viewer@user:tom")),
+			},
+		},
+	})
+	require.NoError(err)
+
+	stream, err := client.ReadRelationships(context.Background(), &v1.ReadRelationshipsRequest{
+		Consistency: &v1.Consistency{
+			Requirement: &v1.Consistency_AtExactSnapshot{AtExactSnapshot: resp.WrittenAt},
+		},
+		RelationshipFilter: &v1.RelationshipFilter{
+			ResourceType:       "document",
+			OptionalResourceId: "totallynew",
+		},
+	})
+	require.NoError(err)
+
+	var found []string
+	for {
+		rel, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(err)
+
+		relStr, err := tuple.StringRelationship(rel.Relationship)
+		require.NoError(err)
+		found = append(found, relStr)
+	}
+	require.ElementsMatch([]string{
+		"document:totallynew#viewer@user:fred",
+		"document:totallynew#viewer@user:sarah",
+	}, found)
+
+	// A failing CREATE rolls back the other updates in the same call.
+	_, err = client.WriteRelationships(context.Background(), &v1.WriteRelationshipsRequest{
+		Updates: []*v1.RelationshipUpdate{
+			{
+				Operation:    v1.RelationshipUpdate_OPERATION_DELETE,
+				Relationship: tuple.MustToRelationship(tuple.MustParse("document:totallynew#viewer@user:sarah")),
+			},
+			{
+				Operation:    v1.RelationshipUpdate_OPERATION_CREATE,
+				Relationship: tuple.MustToRelationship(tuple.MustParse("document:totallynew#viewer@user:fred")),
+			},
+		},
+	})
+	grpcutil.RequireStatus(t, codes.AlreadyExists, err)
+
+	checkResp, err := client.CheckPermission(context.Background(), &v1.CheckPermissionRequest{
+		Consistency: &v1.Consistency{
+			Requirement: &v1.Consistency_FullyConsistent{FullyConsistent: true},
+		},
+		Resource:   &v1.ObjectReference{ObjectType: "document", ObjectId: "totallynew"},
+		Permission: "viewer",
+		Subject:    &v1.SubjectReference{Object: &v1.ObjectReference{ObjectType: "user", ObjectId: "sarah"}},
+	})
+	require.NoError(err)
+	require.Equal(v1.CheckPermissionResponse_PERMISSIONSHIP_HAS_PERMISSION, checkResp.Permissionship)
+}
+
 func TestDeleteRelationshipViaWriteNoop(t *testing.T) {
 	require := require.New(t)
 
@@ -730,6 +967,81 @@ func TestInvalidWriteRelationship(t *testing.T) {
 	}
 }
 
+func TestSubjectRelationEllipsisRepresentation(t *testing.T) {
+	require := require.New(t)
+	conn, cleanup, _, _ := testserver.NewTestServer(require, 0, memdb.DisableGC, true, tf.StandardDatastoreWithSchema)
+	client := v1.NewPermissionsServiceClient(conn)
+	t.Cleanup(cleanup)
+
+	ctx := context.Background()
+
+	// The ellipsis is the internal representation of a subject without a relation;
+	// it cannot be specified over the API.
+	_, err := client.WriteRelationships(ctx, &v1.WriteRelationshipsRequest{
+		Updates: []*v1.RelationshipUpdate{{
+			Operation:    v1.RelationshipUpdate_OPERATION_CREATE,
+			Relationship: rel("document", "newdoc", "viewer", "user", "tom", tuple.Ellipsis),
+		}},
+	})
+	grpcutil.RequireStatus(t, codes.InvalidArgument, err)
+
+	resp, err := client.WriteRelationships(ctx, &v1.WriteRelationshipsRequest{
+		Updates: []*v1.RelationshipUpdate{{
+			Operation:    v1.RelationshipUpdate_OPERATION_CREATE,
+			Relationship: rel("document", "newdoc", "viewer", "user", "tom", ""),
+		}},
+	})
+	require.NoError(err)
+
+	consistency := &v1.Consistency{
+		Requirement: &v1.Consistency_AtLeastAsFresh{AtLeastAsFresh: resp.WrittenAt},
+	}
+
+	// Reading the relationship back returns the subject without a relation, exactly
+	// as written, whether or not the filter specifies the empty relation.
+	for _, subjectFilter := range []*v1.SubjectFilter{
+		{SubjectType: "user", OptionalSubjectId: "tom"},
+		{
+			SubjectType:       "user",
+			OptionalSubjectId: "tom",
+			OptionalRelation:  &v1.SubjectFilter_RelationFilter{Relation: ""},
+		},
+	} {
+		stream, err := client.ReadRelationships(ctx, &v1.ReadRelationshipsRequest{
+			Consistency: consistency,
+			RelationshipFilter: &v1.RelationshipFilter{
+				ResourceType:          "document",
+				OptionalResourceId:    "newdoc",
+				OptionalSubjectFilter: subjectFilter,
+			},
+		})
+		require.NoError(err)
+
+		var found []*v1.Relationship
+		for {
+			readResp, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			require.NoError(err)
+			found = append(found, readResp.Relationship)
+		}
+
+		require.Len(found, 1)
+		require.Equal("", found[0].Subject.OptionalRelation)
+		require.Equal(tuple.MustRelString(rel("document", "newdoc", "viewer", "user", "tom", "")), tuple.MustRelString(found[0]))
+	}
+
+	checkResp, err := client.CheckPermission(ctx, &v1.CheckPermissionRequest{
+		Consistency: consistency,
+		Resource:    obj("document", "newdoc"),
+		Permission:  "view",
+		Subject:     sub("user", "tom", ""),
+	})
+	require.NoError(err)
+	require.Equal(v1.CheckPermissionResponse_PERMISSIONSHIP_HAS_PERMISSION, checkResp.Permissionship)
+}
+
 func TestDeleteRelationships(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -888,6 +1200,39 @@ func TestDeleteRelationships(t *testing.T) {
 				"folder:company#viewer@folder:auditors#viewer": {},
 			},
 		},
+		{
+			name: "delete subject across relations",
+			req: &v1.DeleteRelationshipsRequest{
+				RelationshipFilter: &v1.RelationshipFilter{
+					ResourceType: "document",
+					OptionalSubjectFilter: &v1.SubjectFilter{
+						SubjectType:       "user",
+						OptionalSubjectId: "multiroleguy",
+					},
+				},
+			},
+			deleted: map[string]struct{}{
+				"document:specialplan#viewer_and_editor@user:multiroleguy": {},
+				"document:specialplan#editor@user:multiroleguy":            {},
+			},
+		},
+		{
+			name: "delete subject with limit",
+			req: &v1.DeleteRelationshipsRequest{
+				RelationshipFilter: &v1.RelationshipFilter{
+					ResourceType: "document",
+					OptionalSubjectFilter: &v1.SubjectFilter{
+						SubjectType:       "user",
+						OptionalSubjectId: "multiroleguy",
+					},
+				},
+				OptionalLimit: 2,
+			},
+			deleted: map[string]struct{}{
+				"document:specialplan#viewer_and_editor@user:multiroleguy": {},
+				"document:specialplan#editor@user:multiroleguy":            {},
+			},
+		},
 		{
 			name: "delete unknown relation",
 			req: &v1.DeleteRelationshipsRequest{
@@ -1061,6 +1406,44 @@ func TestDeleteRelationshipsBeyondLimit(t *testing.T) {
 	require.Contains(err.Error(), "found more than 5 relationships to be deleted and partial deletion was not requested")
 }
 
+func TestDeleteRelationshipsBeyondLimitDeletesNothing(t *testing.T) {
+	require := require.New(t)
+	conn, cleanup, ds, _ := testserver.NewTestServer(require, 0, memdb.DisableGC, true, tf.StandardDatastoreWithData)
+	client := v1.NewPermissionsServiceClient(conn)
+	t.Cleanup(cleanup)
+
+	headRev, err := ds.HeadRevision(context.Background())
+	require.NoError(err)
+	before := readOfType(require, "document", client, zedtoken.MustNewFromRevision(headRev))
+
+	_, err = client.DeleteRelationships(context.Background(), &v1.DeleteRelationshipsRequest{
+		RelationshipFilter: &v1.RelationshipFilter{
+			ResourceType: "document",
+		},
+		OptionalLimit: uint32(len(before) - 1),
+	})
+	grpcutil.RequireStatus(t, codes.InvalidArgument, err)
+	spiceerrors.RequireReason(t, v1.ErrorReason_ERROR_REASON_TOO_MANY_RELATIONSHIPS_FOR_TRANSACTIONAL_DELETE, err,
+		"limit",
+		"filter_resource_type",
+	)
+
+	headRev, err = ds.HeadRevision(context.Background())
+	require.NoError(err)
+	require.Equal(before, readOfType(require, "document", client, zedtoken.MustNewFromRevision(headRev)))
+
+	// A limit matching the number of relationships deletes all of them at once.
+	resp, err := client.DeleteRelationships(context.Background(), &v1.DeleteRelationshipsRequest{
+		RelationshipFilter: &v1.RelationshipFilter{
+			ResourceType: "document",
+		},
+		OptionalLimit: uint32(len(before)),
+	})
+	require.NoError(err)
+	require.Equal(v1.DeleteRelationshipsResponse_DELETION_PROGRESS_COMPLETE, resp.DeletionProgress)
+	require.Empty(readOfType(require, "document", client, resp.DeletedAt))
+}
+
 func TestDeleteRelationshipsBeyondAllowedLimit(t *testing.T) {
 	require := require.New(t)
 	conn, cleanup, _, _ := testserver.NewTestServer(require, 0, memdb.DisableGC, true, tf.StandardDatastoreWithData)
@@ -1207,6 +1590,77 @@ func TestDeleteRelationshipsPreconditionsOverLimit(t *testing.T) {
 	require.Contains(err.Error(), "precondition count of 2 is greater than maximum allowed of 1")
 }
 
+func TestDeleteRelationshipsDisallowBroadFilters(t *testing.T) {
+	require := require.New(t)
+	conn, cleanup, ds, _ := testserver.NewTestServerWithConfig(
+		require,
+		testTimedeltas[0],
+		memdb.DisableGC,
+		true,
+		testserver.ServerConfig{
+			MaxPreconditionsCount: 1000,
+			MaxUpdatesPerWrite:    1000,
+			DisallowBroadDeletes:  true,
+		},
+		tf.StandardDatastoreWithData,
+	)
+	client := v1.NewPermissionsServiceClient(conn)
+	t.Cleanup(cleanup)
+
+	headRev, err := ds.HeadRevision(context.Background())
+	require.NoError(err)
+	before := readOfType(require, "document", client, zedtoken.MustNewFromRevision(headRev))
+	require.NotEmpty(before)
+
+	broadFilter := &v1.RelationshipFilter{
+		ResourceType:     "document",
+		OptionalRelation: "viewer",
+	}
+
+	_, err = client.DeleteRelationships(context.Background(), &v1.DeleteRelationshipsRequest{
+		RelationshipFilter: broadFilter,
+	})
+	grpcutil.RequireStatus(t, codes.FailedPrecondition, err)
+	require.ErrorContains(err, "refusing to delete all relationships for resource type `document`")
+
+	headRev, err = ds.HeadRevision(context.Background())
+	require.NoError(err)
+	require.Equal(before, readOfType(require, "document", client, zedtoken.MustNewFromRevision(headRev)))
+
+	// Filters naming a specific resource or subject are allowed.
+	_, err = client.DeleteRelationships(context.Background(), &v1.DeleteRelationshipsRequest{
+		RelationshipFilter: &v1.RelationshipFilter{
+			ResourceType:       "document",
+			OptionalResourceId: "masterplan",
+		},
+	})
+	require.NoError(err)
+
+	_, err = client.DeleteRelationships(context.Background(), &v1.DeleteRelationshipsRequest{
+		RelationshipFilter: &v1.RelationshipFilter{
+			ResourceType: "document",
+			OptionalSubjectFilter: &v1.SubjectFilter{
+				SubjectType:       "user",
+				OptionalSubjectId: "fred",
+			},
+		},
+	})
+	require.NoError(err)
+
+	// Specifying a limit explicitly bounds the deletion, so the broad filter is allowed.
+	resp, err := client.DeleteRelationships(context.Background(), &v1.DeleteRelationshipsRequest{
+		RelationshipFilter:            broadFilter,
+		OptionalLimit:                 1000,
+		OptionalAllowPartialDeletions: true,
+	})
+	require.NoError(err)
+	require.Equal(v1.DeleteRelationshipsResponse_DELETION_PROGRESS_COMPLETE, resp.DeletionProgress)
+
+	for relString := range readOfType(require, "document", client, resp.DeletedAt) {
+		require.NotContains(relString, "#viewer@")
+	}
+}
+
 func TestWriteRelationshipsPreconditionsOverLimit(t *testing.T) {
 	require := require.New(t)
 	conn, cleanup, _, _ := testserver.NewTestServerWithConfig(
@@ -1323,6 +1777,90 @@ func TestWriteRelationshipsCaveatExceedsMaxSize(t *testing.T) {
 	require.ErrorContains(err, "exceeded maximum allowed caveat size of 1")
 }
 
+func TestWriteRelationshipsBatchLimits(t *testing.T) {
+	require := require.New(t)
+	conn, cleanup, _, _ := testserver.NewTestServerWithConfig(
+		require,
+		testTimedeltas[0],
+		memdb.DisableGC,
+		true,
+		testserver.ServerConfig{
+			MaxUpdatesPerWrite:    1000,
+			MaxPreconditionsCount: 1000,
+		},
+		tf.StandardDatastoreWithData,
+	)
+	client := v1.NewPermissionsServiceClient(conn)
+	t.Cleanup(cleanup)
+
+	updatesFor := func(prefix string, count int) []*v1.RelationshipUpdate {
+		updates := make([]*v1.RelationshipUpdate, 0, count)
+		for i := 0; i < count; i++ {
+			updates = append(updates, &v1.RelationshipUpdate{
+				Operation:    v1.RelationshipUpdate_OPERATION_CREATE,
+				Relationship: rel("document", fmt.Sprintf("%s%d", prefix, i), "viewer", "user", "batchuser", ""),
+			})
+		}
+		return updates
+	}
+
+	countBatchRelationships := func(consistency *v1.Consistency) int {
+		stream, err := client.ReadRelationships(context.Background(), &v1.ReadRelationshipsRequest{
+			Consistency: consistency,
+			RelationshipFilter: &v1.RelationshipFilter{
+				ResourceType:     "document",
+				OptionalRelation: "viewer",
+				OptionalSubjectFilter: &v1.SubjectFilter{
+					SubjectType:       "user",
+					OptionalSubjectId: "batchuser",
+				},
+			},
+		})
+		require.NoError(err)
+
+		count := 0
+		for {
+			_, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return count
+			}
+			require.NoError(err)
+			count++
+		}
+	}
+
+	// A batch at the limit is committed at a single revision.
+	resp, err := client.WriteRelationships(context.Background(), &v1.WriteRelationshipsRequest{
+		Updates: updatesFor("batch", 1000),
+	})
+	require.NoError(err)
+	require.Equal(1000, countBatchRelationships(&v1.Consistency{
+		Requirement: &v1.Consistency_AtExactSnapshot{AtExactSnapshot: resp.WrittenAt},
+	}))
+
+	// A batch over the limit is rejected and nothing is written.
+	_, err = client.WriteRelationships(context.Background(), &v1.WriteRelationshipsRequest{
+		Updates: updatesFor("overlimit", 1001),
+	})
+	grpcutil.RequireStatus(t, codes.InvalidArgument, err)
+	require.ErrorContains(err, "update count of 1001 is greater than maximum allowed of 1000")
+
+	// A batch containing an invalid update is rejected as a whole.
+	invalidBatch := updatesFor("invalid", 10)
+	invalidBatch = append(invalidBatch, &v1.RelationshipUpdate{
+		Operation:    v1.RelationshipUpdate_OPERATION_CREATE,
+		Relationship: rel("document", "invalid10", "unknownrelation", "user", "batchuser", ""),
+	})
+	_, err = client.WriteRelationships(context.Background(), &v1.WriteRelationshipsRequest{
+		Updates: invalidBatch,
+	})
+	grpcutil.RequireStatus(t, codes.FailedPrecondition, err)
+
+	require.Equal(1000, countBatchRelationships(&v1.Consistency{
+		Requirement: &v1.Consistency_FullyConsistent{FullyConsistent: true},
+	}))
+}
+
 func TestReadRelationshipsWithTimeout(t *testing.T) {
 	require := require.New(t)
 
@@ -2,6 +2,8 @@ package v1
 
 import (
 	"context"
+	"slices"
+	"strings"
 
 	v1 "github.com/authzed/authzed-go/proto/authzed/api/v1"
 	grpcvalidate "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/validator"
@@ -73,6 +75,15 @@ func (ss *schemaServer) ReadSchema(ctx context.Context, _ *v1.ReadSchemaRequest)
 		return nil, status.Errorf(codes.NotFound, "No schema has been defined; please call WriteSchema to start")
 	}
 
+	// Sort the definitions by name, so that the generated schema is stable regardless of
+	// the order in which the datastore returns them.
+	slices.SortFunc(caveatDefs, func(a, b datastore.RevisionedCaveat) int {
+		return strings.Compare(a.Definition.Name, b.Definition.Name)
+	})
+	slices.SortFunc(nsDefs, func(a, b datastore.RevisionedNamespace) int {
+		return strings.Compare(a.Definition.Name, b.Definition.Name)
+	})
+
 	schemaDefinitions := make([]compiler.SchemaDefinition, 0, len(nsDefs)+len(caveatDefs))
 	for _, caveatDef := range caveatDefs {
 		schemaDefinitions = append(schemaDefinitions, caveatDef.Definition)
@@ -118,6 +129,25 @@ func (ss *schemaServer) WriteSchema(ctx context.Context, in *v1.WriteSchemaReque
 		return nil, ss.rewriteError(ctx, err)
 	}
 
+	// Skip the write if the stored schema already matches, returning the revision at which it
+	// was found.
+	headRevision, err := ds.HeadRevision(ctx)
+	if err != nil {
+		return nil, ss.rewriteError(ctx, err)
+	}
+
+	isNoop, err := shared.SchemaChangesAreNoop(ctx, ds.SnapshotReader(headRevision), validated)
+	if err != nil {
+		return nil, ss.rewriteError(ctx, err)
+	}
+
+	if isNoop {
+		log.Ctx(ctx).Trace().Msg("schema is unchanged; skipping write")
+		return &v1.WriteSchemaResponse{
+			WrittenAt: zedtoken.MustNewFromRevision(headRevision),
+		}, nil
+	}
+
 	// Update the schema.
 	revision, err := ds.ReadWriteTx(ctx, func(ctx context.Context, rwt datastore.ReadWriteTransaction) error {
 		applied, err := shared.ApplySchemaChanges(ctx, rwt, validated)
@@ -2,6 +2,7 @@ package v1_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"google.golang.org/protobuf/types/known/structpb"
@@ -17,6 +18,7 @@ import (
 	core "github.com/authzed/spicedb/pkg/proto/core/v1"
 	"github.com/authzed/spicedb/pkg/spiceerrors"
 	"github.com/authzed/spicedb/pkg/tuple"
+	"github.com/authzed/spicedb/pkg/zedtoken"
 )
 
 func TestSchemaWriteNoPrefix(t *testing.T) {
@@ -85,6 +87,58 @@ func TestSchemaWriteAndReadBack(t *testing.T) {
 	require.NotEmpty(t, readback.ReadAt.Token)
 }
 
+func TestSchemaReadBackIsSortedAndRoundTrips(t *testing.T) {
+	conn, cleanup, _, _ := testserver.NewTestServer(require.New(t), 0, memdb.DisableGC, true, tf.EmptyDatastore)
+	t.Cleanup(cleanup)
+	client := v1.NewSchemaServiceClient(conn)
+
+	// Write a schema with definitions out of alphabetical order.
+	_, err := client.WriteSchema(context.Background(), &v1.WriteSchemaRequest{
+		Schema: `definition user {}
+
+		caveat second(value int) {
+			value == 2
+		}
+
+		definition organization {
+			relation member: user
+		}
+
+		caveat first(value int) {
+			value == 1
+		}
+
+		definition document {
+			relation org: organization
+			relation viewer: user with first | user with second
+			permission view = viewer + org->member
+		}`,
+	})
+	require.NoError(t, err)
+
+	expectedSchema := "caveat first(value int) {\n\tvalue == 1\n}\n\n" +
+		"caveat second(value int) {\n\tvalue == 2\n}\n\n" +
+		"definition document {\n\trelation org: organization\n\trelation viewer: user with first | user with second\n\tpermission view = viewer + org->member\n}\n\n" +
+		"definition organization {\n\trelation member: user\n}\n\n" +
+		"definition user {}"
+
+	readback, err := client.ReadSchema(context.Background(), &v1.ReadSchemaRequest{})
+	require.NoError(t, err)
+	require.Equal(t, expectedSchema, readback.SchemaText)
+	require.NotNil(t, readback.ReadAt)
+	require.NotEmpty(t, readback.ReadAt.Token)
+
+	// Writing the schema read back must succeed and produce the same schema.
+	_, err = client.WriteSchema(context.Background(), &v1.WriteSchemaRequest{
+		Schema: readback.SchemaText,
+	})
+	require.NoError(t, err)
+
+	secondReadback, err := client.ReadSchema(context.Background(), &v1.ReadSchemaRequest{})
+	require.NoError(t, err)
+	require.Equal(t, expectedSchema, secondReadback.SchemaText)
+}
+
 func TestSchemaDeleteRelation(t *testing.T) {
 	conn, cleanup, _, _ := testserver.NewTestServer(require.New(t), 0, memdb.DisableGC, true, tf.EmptyDatastore)
 	t.Cleanup(cleanup)
@@ -362,6 +416,77 @@ definition example/user {}`
 	require.Equal(t, newSchema, readback.SchemaText)
 }
 
+func TestSchemaRemoveAllowedSubjectType(t *testing.T) {
+	conn, cleanup, _, _ := testserver.NewTestServer(require.New(t), 0, memdb.DisableGC, true, tf.EmptyDatastore)
+	t.Cleanup(cleanup)
+	client := v1.NewSchemaServiceClient(conn)
+	v1client := v1.NewPermissionsServiceClient(conn)
+
+	originalSchema := `definition example/document {
+	relation viewer: example/user | example/group#member
+}
+
+definition example/group {
+	relation member: example/user
+}
+
+definition example/user {}`
+
+	// Write a schema allowing both users and group members as viewers.
+	_, err := client.WriteSchema(context.Background(), &v1.WriteSchemaRequest{
+		Schema: originalSchema,
+	})
+	require.NoError(t, err)
+
+	// Write a relationship using the group member subject type.
+	_, err = v1client.WriteRelationships(context.Background(), &v1.WriteRelationshipsRequest{
+		Updates: []*v1.RelationshipUpdate{tuple.UpdateToRelationshipUpdate(tuple.Create(
+			tuple.MustParse("example/document:somedoc#viewer@example/group:somegroup#member"),
+		))},
+	})
+	require.Nil(t, err)
+
+	narrowedSchema := `definition example/document {
+	relation viewer: example/user
+}
+
+definition example/group {
+	relation member: example/user
+}
+
+definition example/user {}`
+
+	// Attempt to narrow the allowed subject types, which should fail.
+	_, err = client.WriteSchema(context.Background(), &v1.WriteSchemaRequest{
+		Schema: narrowedSchema,
+	})
+	grpcutil.RequireStatus(t, codes.InvalidArgument, err)
+	require.Equal(t, "rpc error: code = InvalidArgument desc = cannot remove allowed type `example/group#member` from relation `viewer` in object definition `example/document`, as a relationship exists with it", err.Error())
+
+	// Ensure the original schema was left in place.
+	readback, err := client.ReadSchema(context.Background(), &v1.ReadSchemaRequest{})
+	require.NoError(t, err)
+	require.Equal(t, originalSchema, readback.SchemaText)
+
+	// Delete the relationship.
+	_, err = v1client.WriteRelationships(context.Background(), &v1.WriteRelationshipsRequest{
+		Updates: []*v1.RelationshipUpdate{tuple.UpdateToRelationshipUpdate(tuple.Delete(
+			tuple.MustParse("example/document:somedoc#viewer@example/group:somegroup#member"),
+		))},
+	})
+	require.Nil(t, err)
+
+	// Attempt to narrow the allowed subject types, which should work now.
+	_, err = client.WriteSchema(context.Background(), &v1.WriteSchemaRequest{
+		Schema: narrowedSchema,
+	})
+	require.Nil(t, err)
+
+	readback, err = client.ReadSchema(context.Background(), &v1.ReadSchemaRequest{})
+	require.NoError(t, err)
+	require.Equal(t, narrowedSchema, readback.SchemaText)
+}
+
 func TestSchemaEmpty(t *testing.T) {
 	conn, cleanup, _, _ := testserver.NewTestServer(require.New(t), 0, memdb.DisableGC, true, tf.EmptyDatastore)
 	t.Cleanup(cleanup)
@@ -564,6 +689,102 @@ func TestSchemaUnchangedNamespaces(t *testing.T) {
 	require.True(t, docRevision.GreaterThan(userRevision))
 }
 
+func TestSchemaWriteIdenticalSchemaDoesNotAdvanceRevision(t *testing.T) {
+	conn, cleanup, ds, _ := testserver.NewTestServer(require.New(t), 0, memdb.DisableGC, true, tf.EmptyDatastore)
+	t.Cleanup(cleanup)
+
+	client := v1.NewSchemaServiceClient(conn)
+
+	schema := `definition user {}
+
+		definition organization {
+			relation member: user
+		}
+
+		/** document is a document */
+		definition document {
+			relation viewer: user | organization#member
+		}`
+
+	first, err := client.WriteSchema(context.Background(), &v1.WriteSchemaRequest{Schema: schema})
+	require.NoError(t, err)
+
+	// Writing the same schema again must not write to the datastore.
+	second, err := client.WriteSchema(context.Background(), &v1.WriteSchemaRequest{Schema: schema})
+	require.NoError(t, err)
+	require.Equal(t, first.WrittenAt.Token, second.WrittenAt.Token)
+
+	head, err := ds.HeadRevision(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, first.WrittenAt.Token, zedtoken.MustNewFromRevision(head).Token)
+
+	// A change to only a comment is still written.
+	third, err := client.WriteSchema(context.Background(), &v1.WriteSchemaRequest{
+		Schema: strings.Replace(schema, "document is a document", "document is a file", 1),
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, first.WrittenAt.Token, third.WrittenAt.Token)
+
+	// As is the removal of a definition.
+	fourth, err := client.WriteSchema(context.Background(), &v1.WriteSchemaRequest{
+		Schema: `definition user {}
+
+		definition document {
+			relation viewer: user
+		}`,
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, third.WrittenAt.Token, fourth.WrittenAt.Token)
+
+	readback, err := client.ReadSchema(context.Background(), &v1.ReadSchemaRequest{})
+	require.NoError(t, err)
+	require.NotContains(t, readback.SchemaText, "organization")
+}
+
+func TestSchemaWriteArrows(t *testing.T) {
+	conn, cleanup, _, _ := testserver.NewTestServer(require.New(t), 0, memdb.DisableGC, true, tf.EmptyDatastore)
+	t.Cleanup(cleanup)
+
+	client := v1.NewSchemaServiceClient(conn)
+
+	// An arrow over a relation that does not exist is a type error.
+	_, err := client.WriteSchema(context.Background(), &v1.WriteSchemaRequest{
+		Schema: `definition user {}
+
+		definition folder {
+			relation admin: user
+		}
+
+		definition document {
+			relation parent: folder
+			permission edit = parents->admin
+		}`,
+	})
+	grpcutil.RequireStatus(t, codes.FailedPrecondition, err)
+	spiceerrors.RequireReason(t, v1.ErrorReason_ERROR_REASON_SCHEMA_TYPE_ERROR, err,
+		"definition_name",
+		"relation_or_permission_name",
+	)
+	require.ErrorContains(t, err, "relation/permission `parents` not found under definition `document`")
+
+	// The right side of an arrow is not required to exist on every, or any, subject type
+	// of the relation, as it is evaluated against whichever type is found.
+	_, err = client.WriteSchema(context.Background(), &v1.WriteSchemaRequest{
+		Schema: `definition user {}
+
+		definition folder {
+			relation admin: user
+		}
+
+		definition document {
+			relation parent: folder
+			permission edit = parent->admin
+			permission manage = parent->manager
+		}`,
+	})
+	require.NoError(t, err)
+}
+
 func TestSchemaInvalid(t *testing.T) {
 	conn, cleanup, _, _ := testserver.NewTestServer(require.New(t), 0, memdb.DisableGC, false, tf.EmptyDatastore)
 	t.Cleanup(cleanup)
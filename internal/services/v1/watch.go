@@ -53,6 +53,15 @@ func (ws *watchServer) Watch(req *v1.WatchRequest, stream v1.WatchService_WatchS
 			return status.Errorf(codes.InvalidArgument, "failed to decode start revision: %s", err)
 		}
 
+		if err := ds.CheckRevision(ctx, decodedRevision); err != nil {
+			// Only a revision outside the valid range means the start cursor itself is unusable.
+			if errors.As(err, &datastore.ErrInvalidRevision{}) {
+				return status.Errorf(codes.FailedPrecondition, "invalid start revision: %s", err)
+			}
+
+			return shared.RewriteError(ctx, err, nil)
+		}
+
 		afterRevision = decodedRevision
 	} else {
 		var err error
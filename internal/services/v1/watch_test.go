@@ -86,6 +86,31 @@ func TestWatch(t *testing.T) {
 				update(v1.RelationshipUpdate_OPERATION_TOUCH, "document", "document2", "viewer", "user", "user1"),
 			},
 		},
+		{
+			name:              "watch with multiple objectType filters",
+			expectedCode:      codes.OK,
+			objectTypesFilter: []string{"document", "folder"},
+			mutations: []*v1.RelationshipUpdate{
+				update(v1.RelationshipUpdate_OPERATION_CREATE, "document", "document1", "viewer", "user", "user1"),
+				update(v1.RelationshipUpdate_OPERATION_DELETE, "folder", "auditors", "viewer", "user", "auditor"),
+			},
+			expectedUpdates: []*v1.RelationshipUpdate{
+				update(v1.RelationshipUpdate_OPERATION_TOUCH, "document", "document1", "viewer", "user", "user1"),
+				update(v1.RelationshipUpdate_OPERATION_DELETE, "folder", "auditors", "viewer", "user", "auditor"),
+			},
+		},
+		{
+			name:              "watch with objectType filter matching only subjects",
+			expectedCode:      codes.OK,
+			objectTypesFilter: []string{"folder"},
+			mutations: []*v1.RelationshipUpdate{
+				update(v1.RelationshipUpdate_OPERATION_CREATE, "document", "document1", "parent", "folder", "folder1"),
+				update(v1.RelationshipUpdate_OPERATION_TOUCH, "folder", "folder2", "viewer", "user", "user1"),
+			},
+			expectedUpdates: []*v1.RelationshipUpdate{
+				update(v1.RelationshipUpdate_OPERATION_TOUCH, "folder", "folder2", "viewer", "user", "user1"),
+			},
+		},
 		{
 			name:         "invalid zedtoken",
 			startCursor:  &v1.ZedToken{Token: "bad-token"},
@@ -175,6 +200,73 @@ func TestWatch(t *testing.T) {
 	}
 }
 
+func TestWatchStreamsUpdatesInRevisionOrder(t *testing.T) {
+	require := require.New(t)
+
+	conn, cleanup, ds, revision := testserver.NewTestServer(require, 0, memdb.DisableGC, true, testfixtures.StandardDatastoreWithData)
+	t.Cleanup(cleanup)
+	client := v1.NewWatchServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.Watch(ctx, &v1.WatchRequest{
+		OptionalStartCursor: zedtoken.MustNewFromRevision(revision),
+	})
+	require.NoError(err)
+
+	mutations := []*v1.RelationshipUpdate{
+		update(v1.RelationshipUpdate_OPERATION_TOUCH, "document", "document1", "viewer", "user", "user1"),
+		update(v1.RelationshipUpdate_OPERATION_TOUCH, "document", "document2", "viewer", "user", "user1"),
+		update(v1.RelationshipUpdate_OPERATION_TOUCH, "document", "document3", "viewer", "user", "user1"),
+	}
+
+	// Write each relationship in its own transaction, so that each is reported at its own revision.
+	for _, mutation := range mutations {
+		_, err := v1.NewPermissionsServiceClient(conn).WriteRelationships(context.Background(), &v1.WriteRelationshipsRequest{
+			Updates: []*v1.RelationshipUpdate{mutation},
+		})
+		require.NoError(err)
+	}
+
+	lastRevision := revision
+	for _, expected := range mutations {
+		resp, err := stream.Recv()
+		require.NoError(err)
+		require.Equal([]*v1.RelationshipUpdate{expected}, resp.Updates)
+
+		changesThrough, err := zedtoken.DecodeRevision(resp.ChangesThrough, ds)
+		require.NoError(err)
+		require.True(changesThrough.GreaterThan(lastRevision), "expected revision %s to be after %s", changesThrough, lastRevision)
+		lastRevision = changesThrough
+	}
+}
+
+func TestWatchFromRevisionOutsideGCWindow(t *testing.T) {
+	require := require.New(t)
+
+	conn, cleanup, _, revision := testserver.NewTestServer(require, 0, 10*time.Millisecond, true, testfixtures.StandardDatastoreWithData)
+	t.Cleanup(cleanup)
+
+	// Move the head revision forward, so that the starting revision is no longer the head.
+	_, err := v1.NewPermissionsServiceClient(conn).WriteRelationships(context.Background(), &v1.WriteRelationshipsRequest{
+		Updates: []*v1.RelationshipUpdate{
+			update(v1.RelationshipUpdate_OPERATION_TOUCH, "document", "document1", "viewer", "user", "user1"),
+		},
+	})
+	require.NoError(err)
+
+	time.Sleep(20 * time.Millisecond)
+
+	stream, err := v1.NewWatchServiceClient(conn).Watch(context.Background(), &v1.WatchRequest{
+		OptionalStartCursor: zedtoken.MustNewFromRevision(revision),
+	})
+	require.NoError(err)
+
+	_, err = stream.Recv()
+	grpcutil.RequireStatus(t, codes.FailedPrecondition, err)
+}
+
 func sortUpdates(in []*v1.RelationshipUpdate) []*v1.RelationshipUpdate {
 	out := make([]*v1.RelationshipUpdate, 0, len(in))
 	out = append(out, in...)
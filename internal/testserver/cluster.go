@@ -27,16 +27,6 @@ import (
 
 const TestResolverScheme = "test"
 
-type TempError struct{}
-
-func (t TempError) Error() string {
-	return "no dialers yet"
-}
-
-func (t TempError) Temporary() bool {
-	return true
-}
-
 type dialerFunc func(ctx context.Context, s string) (net.Conn, error)
 
 // track prefixes used for making test clusters to avoid registering the same
@@ -92,6 +82,13 @@ func (b *SafeManualResolverBuilder) Build(target resolver.Target, cc resolver.Cl
 		addrs:  addrs,
 	}
 	b.resolvers.Store(target.URL.Hostname(), r)
+
+	// Each node of a cluster builds its own resolver for the same prefix, and the
+	// resolver may be built lazily after ResolveNow has been called, so push the
+	// known addresses to the new connection right away.
+	if ok {
+		r.ResolveNow(resolver.ResolveNowOptions{})
+	}
 	return r, nil
 }
 
@@ -156,7 +153,14 @@ func TestClusterWithDispatchAndCacheConfig(t testing.TB, size uint, ds datastore
 	}
 	testResolverBuilder.SetAddrs(prefix, addresses)
 
-	dialers := make([]dialerFunc, 0, size)
+	// dialers are set as each node is started; dialerReady[i] is closed once
+	// dialers[i] is available.
+	dialers := make([]dialerFunc, size)
+	dialerReady := make([]chan struct{}, size)
+	for i := range dialerReady {
+		dialerReady[i] = make(chan struct{})
+	}
+
 	conns := make([]*grpc.ClientConn, 0, size)
 	cancelFuncs := make([]func(), 0, size)
 
@@ -171,17 +175,19 @@ func TestClusterWithDispatchAndCacheConfig(t testing.TB, size uint, ds datastore
 						Spread:            1,
 					}).MustServiceConfigJSON()),
 				grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
-					// it's possible grpc tries to dial before we have set the
-					// buffconn dialers, we have to return a "TempError" so that
-					// grpc knows to retry the connection.
-					if len(dialers) == 0 {
-						return nil, TempError{}
-					}
 					// "s" here will be the address from the manual resolver
 					// like `<prefix>_<node number>`
 					i, err := strconv.Atoi(strings.TrimPrefix(s, prefix+"_"))
 					require.NoError(t, err)
-					return dialers[i](ctx, s)
+
+					// it's possible grpc tries to dial before the node has been
+					// started, so wait for its buffconn dialer to be set.
+					select {
+					case <-dialerReady[i]:
+						return dialers[i](ctx, s)
+					case <-ctx.Done():
+						return nil, ctx.Err()
+					}
 				}),
 			),
 		}
@@ -222,7 +228,8 @@ func TestClusterWithDispatchAndCacheConfig(t testing.TB, size uint, ds datastore
 		}()
 		cancelFuncs = append(cancelFuncs, cancel)
 
-		dialers = append(dialers, srv.DispatchNetDialContext)
+		dialers[i] = srv.DispatchNetDialContext
+		close(dialerReady[i])
 		conn, err := srv.GRPCDialContext(ctx,
 			grpc.WithReturnConnectionError(),
 			grpc.WithBlock(),
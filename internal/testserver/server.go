@@ -25,6 +25,7 @@ type ServerConfig struct {
 	MaxPreconditionsCount      uint16
 	MaxRelationshipContextSize int
 	StreamingAPITimeout        time.Duration
+	DisallowBroadDeletes       bool
 }
 
 // NewTestServer creates a new test server, using defaults for the config.
@@ -65,6 +66,7 @@ func NewTestServerWithConfig(require *require.Assertions,
 		server.WithStreamingAPITimeout(config.StreamingAPITimeout),
 		server.WithMaxCaveatContextSize(4096),
 		server.WithMaxRelationshipContextSize(config.MaxRelationshipContextSize),
+		server.WithDisallowBroadDeletes(config.DisallowBroadDeletes),
 		server.WithGRPCServer(util.GRPCServerConfig{
 			Network: util.BufferedNetwork,
 			Enabled: true,
@@ -122,6 +122,7 @@ func RegisterServeFlags(cmd *cobra.Command, config *server.Config) error {
 
 	cmd.Flags().StringToStringVar(&config.DispatchSecondaryUpstreamAddrs, "experimental-dispatch-secondary-upstream-addrs", nil, "secondary upstream addresses for dispatches, each with a name")
 	cmd.Flags().StringToStringVar(&config.DispatchSecondaryUpstreamExprs, "experimental-dispatch-secondary-upstream-exprs", nil, "map from request type (currently supported: `check`) to its associated CEL expression, which returns the secondary upstream(s) to be used for the request")
+	cmd.Flags().DurationVar(&config.DispatchSecondaryUpstreamDelay, "experimental-dispatch-secondary-upstream-delay", 0, "duration to wait for the primary dispatch to succeed before issuing any secondary dispatches; if zero, secondary dispatches are issued immediately")
 
 	// Flags for configuring API behavior
 	cmd.Flags().BoolVar(&config.DisableV1SchemaAPI, "disable-v1-schema-api", false, "disables the V1 schema API")
@@ -131,6 +132,7 @@ func RegisterServeFlags(cmd *cobra.Command, config *server.Config) error {
 	cmd.Flags().IntVar(&config.MaxCaveatContextSize, "max-caveat-context-size", 4096, "maximum allowed size of request caveat context in bytes. A value of zero or less means no limit")
 	cmd.Flags().IntVar(&config.MaxRelationshipContextSize, "max-relationship-context-size", 25000, "maximum allowed size of the context to be stored in a relationship")
 	cmd.Flags().DurationVar(&config.StreamingAPITimeout, "streaming-api-response-delay-timeout", 30*time.Second, "max duration time elapsed between messages sent by the server-side to the client (responses) before the stream times out")
+	cmd.Flags().BoolVar(&config.DisallowBroadDeletes, "delete-relationships-disallow-broad-filters", false, "reject DeleteRelationships calls without a limit whose filter has neither a resource ID nor a subject filter")
 	cmd.Flags().DurationVar(&config.WatchHeartbeat, "watch-api-heartbeat", 1*time.Second, "heartbeat time on the watch in the API. 0 means to default to the datastore's minimum.")
 
 	cmd.Flags().BoolVar(&config.V1SchemaAdditiveOnly, "testing-only-schema-additive-writes", false, "append new definitions to the existing schema, rather than overwriting it")
@@ -140,6 +142,7 @@ func RegisterServeFlags(cmd *cobra.Command, config *server.Config) error {
 
 	// Flags for misc services
 	util.RegisterHTTPServerFlags(cmd.Flags(), &config.MetricsAPI, "metrics", "metrics", ":9090", true)
+	cmd.Flags().BoolVar(&config.CheckMetricsObjectTypeLabelEnabled, "metrics-check-object-type-label-enabled", false, "label the CheckPermission metrics by the object type of the checked resource; may result in a high number of metric series")
 
 	if err := util.RegisterDeprecatedHTTPServerFlags(cmd, "dashboard", "dashboard"); err != nil {
 		return err
@@ -21,7 +21,6 @@ import (
 	"github.com/rs/cors"
 	"github.com/rs/zerolog"
 	"github.com/sean-/sysexits"
-	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 	_ "google.golang.org/grpc/encoding/gzip" // enable gzip compression on all derivative servers
@@ -100,6 +99,7 @@ type Config struct {
 
 	DispatchSecondaryUpstreamAddrs map[string]string `debugmap:"visible"`
 	DispatchSecondaryUpstreamExprs map[string]string `debugmap:"visible"`
+	DispatchSecondaryUpstreamDelay time.Duration     `debugmap:"visible"`
 
 	DispatchCacheConfig        CacheConfig `debugmap:"visible"`
 	ClusterDispatchCacheConfig CacheConfig `debugmap:"visible"`
@@ -112,9 +112,11 @@ type Config struct {
 	MaxDatastoreReadPageSize uint64        `debugmap:"visible"`
 	StreamingAPITimeout      time.Duration `debugmap:"visible"`
 	WatchHeartbeat           time.Duration `debugmap:"visible"`
+	DisallowBroadDeletes     bool          `debugmap:"visible"`
 
 	// Additional Services
-	MetricsAPI util.HTTPServerConfig `debugmap:"visible"`
+	MetricsAPI                         util.HTTPServerConfig `debugmap:"visible"`
+	CheckMetricsObjectTypeLabelEnabled bool                  `debugmap:"visible"`
 
 	// Middleware for grpc API
 	UnaryMiddlewareModification     []MiddlewareModification[grpc.UnaryServerInterceptor]  `debugmap:"hidden"`
@@ -273,9 +275,9 @@ func (c *Config) Complete(ctx context.Context) (RunnableServer, error) {
 			combineddispatch.UpstreamCAPath(c.DispatchUpstreamCAPath),
 			combineddispatch.SecondaryUpstreamAddrs(c.DispatchSecondaryUpstreamAddrs),
 			combineddispatch.SecondaryUpstreamExprs(c.DispatchSecondaryUpstreamExprs),
+			combineddispatch.SecondaryUpstreamDelay(c.DispatchSecondaryUpstreamDelay),
 			combineddispatch.GrpcPresharedKey(dispatchPresharedKey),
 			combineddispatch.GrpcDialOpts(
-				grpc.WithUnaryInterceptor(otelgrpc.UnaryClientInterceptor()), // nolint: staticcheck
 				grpc.WithDefaultServiceConfig(hashringConfigJSON),
 			),
 			combineddispatch.MetricsEnabled(c.DispatchClientMetricsEnabled),
@@ -401,6 +403,9 @@ func (c *Config) Complete(ctx context.Context) (RunnableServer, error) {
 		MaxRelationshipContextSize: c.MaxRelationshipContextSize,
 		MaxDatastoreReadPageSize:   c.MaxDatastoreReadPageSize,
 		StreamingAPITimeout:        c.StreamingAPITimeout,
+		DisallowBroadDeletes:       c.DisallowBroadDeletes,
+
+		CheckMetricsObjectTypeLabelEnabled: c.CheckMetricsObjectTypeLabelEnabled,
 	}
 
 	healthManager := health.NewHealthManager(dispatcher, ds)
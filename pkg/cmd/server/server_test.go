@@ -165,6 +165,7 @@ func TestServerGracefulTerminationOnError(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	ds, err := memdb.NewMemdbDatastore(0, 1*time.Second, 10*time.Second)
 	require.NoError(t, err)
+	defer ds.Close()
 
 	c := ConfigWithOptions(&Config{
 		GRPCServer: util.GRPCServerConfig{
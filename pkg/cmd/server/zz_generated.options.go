@@ -72,6 +72,7 @@ func (c *Config) ToOption() ConfigOption {
 		to.DispatchHashringSpread = c.DispatchHashringSpread
 		to.DispatchSecondaryUpstreamAddrs = c.DispatchSecondaryUpstreamAddrs
 		to.DispatchSecondaryUpstreamExprs = c.DispatchSecondaryUpstreamExprs
+		to.DispatchSecondaryUpstreamDelay = c.DispatchSecondaryUpstreamDelay
 		to.DispatchCacheConfig = c.DispatchCacheConfig
 		to.ClusterDispatchCacheConfig = c.ClusterDispatchCacheConfig
 		to.DisableV1SchemaAPI = c.DisableV1SchemaAPI
@@ -81,7 +82,9 @@ func (c *Config) ToOption() ConfigOption {
 		to.MaxDatastoreReadPageSize = c.MaxDatastoreReadPageSize
 		to.StreamingAPITimeout = c.StreamingAPITimeout
 		to.WatchHeartbeat = c.WatchHeartbeat
+		to.DisallowBroadDeletes = c.DisallowBroadDeletes
 		to.MetricsAPI = c.MetricsAPI
+		to.CheckMetricsObjectTypeLabelEnabled = c.CheckMetricsObjectTypeLabelEnabled
 		to.UnaryMiddlewareModification = c.UnaryMiddlewareModification
 		to.StreamingMiddlewareModification = c.StreamingMiddlewareModification
 		to.DispatchUnaryMiddleware = c.DispatchUnaryMiddleware
@@ -132,6 +135,7 @@ func (c Config) DebugMap() map[string]any {
 	debugMap["DispatchHashringSpread"] = helpers.DebugValue(c.DispatchHashringSpread, false)
 	debugMap["DispatchSecondaryUpstreamAddrs"] = helpers.DebugValue(c.DispatchSecondaryUpstreamAddrs, false)
 	debugMap["DispatchSecondaryUpstreamExprs"] = helpers.DebugValue(c.DispatchSecondaryUpstreamExprs, false)
+	debugMap["DispatchSecondaryUpstreamDelay"] = helpers.DebugValue(c.DispatchSecondaryUpstreamDelay, false)
 	debugMap["DispatchCacheConfig"] = helpers.DebugValue(c.DispatchCacheConfig, false)
 	debugMap["ClusterDispatchCacheConfig"] = helpers.DebugValue(c.ClusterDispatchCacheConfig, false)
 	debugMap["DisableV1SchemaAPI"] = helpers.DebugValue(c.DisableV1SchemaAPI, false)
@@ -141,7 +145,9 @@ func (c Config) DebugMap() map[string]any {
 	debugMap["MaxDatastoreReadPageSize"] = helpers.DebugValue(c.MaxDatastoreReadPageSize, false)
 	debugMap["StreamingAPITimeout"] = helpers.DebugValue(c.StreamingAPITimeout, false)
 	debugMap["WatchHeartbeat"] = helpers.DebugValue(c.WatchHeartbeat, false)
+	debugMap["DisallowBroadDeletes"] = helpers.DebugValue(c.DisallowBroadDeletes, false)
 	debugMap["MetricsAPI"] = helpers.DebugValue(c.MetricsAPI, false)
+	debugMap["CheckMetricsObjectTypeLabelEnabled"] = helpers.DebugValue(c.CheckMetricsObjectTypeLabelEnabled, false)
 	debugMap["SilentlyDisableTelemetry"] = helpers.DebugValue(c.SilentlyDisableTelemetry, false)
 	debugMap["TelemetryCAOverridePath"] = helpers.DebugValue(c.TelemetryCAOverridePath, false)
 	debugMap["TelemetryEndpoint"] = helpers.DebugValue(c.TelemetryEndpoint, false)
@@ -433,6 +439,13 @@ func SetDispatchSecondaryUpstreamExprs(dispatchSecondaryUpstreamExprs map[string
 	}
 }
 
+// WithDispatchSecondaryUpstreamDelay returns an option that can set DispatchSecondaryUpstreamDelay on a Config
+func WithDispatchSecondaryUpstreamDelay(dispatchSecondaryUpstreamDelay time.Duration) ConfigOption {
+	return func(c *Config) {
+		c.DispatchSecondaryUpstreamDelay = dispatchSecondaryUpstreamDelay
+	}
+}
+
 // WithDispatchCacheConfig returns an option that can set DispatchCacheConfig on a Config
 func WithDispatchCacheConfig(dispatchCacheConfig CacheConfig) ConfigOption {
 	return func(c *Config) {
@@ -496,6 +509,13 @@ func WithWatchHeartbeat(watchHeartbeat time.Duration) ConfigOption {
 	}
 }
 
+// WithDisallowBroadDeletes returns an option that can set DisallowBroadDeletes on a Config
+func WithDisallowBroadDeletes(disallowBroadDeletes bool) ConfigOption {
+	return func(c *Config) {
+		c.DisallowBroadDeletes = disallowBroadDeletes
+	}
+}
+
 // WithMetricsAPI returns an option that can set MetricsAPI on a Config
 func WithMetricsAPI(metricsAPI util.HTTPServerConfig) ConfigOption {
 	return func(c *Config) {
@@ -503,6 +523,13 @@ func WithMetricsAPI(metricsAPI util.HTTPServerConfig) ConfigOption {
 	}
 }
 
+// WithCheckMetricsObjectTypeLabelEnabled returns an option that can set CheckMetricsObjectTypeLabelEnabled on a Config
+func WithCheckMetricsObjectTypeLabelEnabled(checkMetricsObjectTypeLabelEnabled bool) ConfigOption {
+	return func(c *Config) {
+		c.CheckMetricsObjectTypeLabelEnabled = checkMetricsObjectTypeLabelEnabled
+	}
+}
+
 // WithUnaryMiddlewareModification returns an option that can append UnaryMiddlewareModifications to Config.UnaryMiddlewareModification
 func WithUnaryMiddlewareModification(unaryMiddlewareModification MiddlewareModification[grpc.UnaryServerInterceptor]) ConfigOption {
 	return func(c *Config) {
@@ -146,6 +146,7 @@ func (c *Config) Complete() (RunnableTestServer, error) {
 		gatewayServer:         gatewayServer,
 		readOnlyGatewayServer: readOnlyGatewayServer,
 		healthManager:         healthManager,
+		datastoreMiddleware:   datastoreMiddleware,
 	}, nil
 }
 
@@ -156,7 +157,8 @@ type completedTestServer struct {
 	gatewayServer         util.RunnableHTTPServer
 	readOnlyGatewayServer util.RunnableHTTPServer
 
-	healthManager health.Manager
+	healthManager       health.Manager
+	datastoreMiddleware *pertoken.MiddlewareForTesting
 }
 
 func (c *completedTestServer) Run(ctx context.Context) error {
@@ -188,6 +190,10 @@ func (c *completedTestServer) Run(ctx context.Context) error {
 		log.Ctx(ctx).Warn().Err(err).Msg("error shutting down servers")
 	}
 
+	if err := c.datastoreMiddleware.Close(); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("error closing per-token datastores")
+	}
+
 	return nil
 }
 
@@ -46,6 +46,8 @@ type GRPCServerConfig struct {
 	ClientCAPath string        `debugmap:"visible"`
 	MaxWorkers   uint32        `debugmap:"visible"`
 
+	GracefulStopTimeout time.Duration `debugmap:"visible"`
+
 	flagPrefix string
 }
 
@@ -55,6 +57,7 @@ type GRPCServerConfig struct {
 // - "$PREFIX-tls-cert-path"
 // - "$PREFIX-tls-key-path"
 // - "$PREFIX-max-conn-age"
+// - "$PREFIX-graceful-stop-timeout"
 func RegisterGRPCServerFlags(flags *pflag.FlagSet, config *GRPCServerConfig, flagPrefix, serviceName, defaultAddr string, defaultEnabled bool) {
 	flagPrefix = stringz.DefaultEmpty(flagPrefix, "grpc")
 	serviceName = stringz.DefaultEmpty(serviceName, "grpc")
@@ -68,6 +71,7 @@ func RegisterGRPCServerFlags(flags *pflag.FlagSet, config *GRPCServerConfig, fla
 	flags.DurationVar(&config.MaxConnAge, flagPrefix+"-max-conn-age", 30*time.Second, "how long a connection serving "+serviceName+" should be able to live")
 	flags.BoolVar(&config.Enabled, flagPrefix+"-enabled", defaultEnabled, "enable "+serviceName+" gRPC server")
 	flags.Uint32Var(&config.MaxWorkers, flagPrefix+"-max-workers", 0, "set the number of workers for this server (0 value means 1 worker per request)")
+	flags.DurationVar(&config.GracefulStopTimeout, flagPrefix+"-graceful-stop-timeout", 0, "how long in-flight requests to "+serviceName+" are given to complete when stopping, after which they are canceled (0 value means no limit)")
 }
 
 type (
@@ -128,12 +132,38 @@ func (c *GRPCServerConfig) Complete(level zerolog.Level, svcRegistrationFn func(
 				Str("service", c.flagPrefix).
 				Msg("grpc server stopped serving")
 		},
-		stopFunc:    srv.GracefulStop,
-		creds:       clientCreds,
-		certWatcher: certWatcher,
+		stopFunc:            gracefulStop(srv, c.GracefulStopTimeout),
+		gracefulStopTimeout: c.GracefulStopTimeout,
+		creds:               clientCreds,
+		certWatcher:         certWatcher,
 	}, nil
 }
 
+// gracefulStop returns a function that stops the server from accepting new RPCs and
+// waits for in-flight RPCs to complete. If the timeout is non-zero and elapses before
+// they complete, the remaining RPCs are canceled.
+func gracefulStop(srv *grpc.Server, timeout time.Duration) func() {
+	return func() {
+		if timeout == 0 {
+			srv.GracefulStop()
+			return
+		}
+
+		stopped := make(chan struct{})
+		go func() {
+			srv.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-time.After(timeout):
+			srv.Stop()
+			<-stopped
+		}
+	}
+}
+
 func (c *GRPCServerConfig) listenerAndDialer() (net.Listener, DialFunc, NetDialFunc, error) {
 	if c.Network == BufferedNetwork {
 		bl := bufconn.Listen(c.BufferSize)
@@ -217,6 +247,8 @@ type completedGRPCServer struct {
 	netDial           func(ctx context.Context, s string) (net.Conn, error)
 	creds             credentials.TransportCredentials
 	certWatcher       *certwatcher.CertWatcher
+
+	gracefulStopTimeout time.Duration
 }
 
 // WithOpts adds to the options for running the server
@@ -227,7 +259,7 @@ func (c *completedGRPCServer) WithOpts(opts ...grpc.ServerOption) RunnableGRPCSe
 	c.listenFunc = func() error {
 		return srv.Serve(c.listener)
 	}
-	c.stopFunc = srv.GracefulStop
+	c.stopFunc = gracefulStop(srv, c.gracefulStopTimeout)
 	return c
 }
 
@@ -259,7 +291,8 @@ func (c *completedGRPCServer) Insecure() bool {
 	return c.creds.Info().SecurityProtocol == "insecure"
 }
 
-// GracefulStop stops a running server
+// GracefulStop stops a running server, waiting for in-flight requests to complete
+// for at most the configured graceful stop timeout
 func (c *completedGRPCServer) GracefulStop() {
 	c.prestopFunc()
 	c.stopFunc()
@@ -3,9 +3,15 @@ package util
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 func TestDisabledGRPC(t *testing.T) {
@@ -22,3 +28,86 @@ func TestDisabledHTTP(t *testing.T) {
 	require.NoError(t, s.ListenAndServe())
 	s.Close()
 }
+
+func TestGRPCGracefulStopTimeout(t *testing.T) {
+	// slowHandler answers any unknown method after the requested delay, or fails once the
+	// server cancels the call. It signals on started once a call has been received.
+	slowHandler := func(started chan<- struct{}) grpc.StreamHandler {
+		return func(_ any, stream grpc.ServerStream) error {
+			delay := &durationpb.Duration{}
+			if err := stream.RecvMsg(delay); err != nil {
+				return err
+			}
+
+			select {
+			case started <- struct{}{}:
+			default:
+			}
+
+			select {
+			case <-time.After(delay.AsDuration()):
+				return stream.SendMsg(&emptypb.Empty{})
+			case <-stream.Context().Done():
+				return stream.Context().Err()
+			}
+		}
+	}
+
+	for _, tc := range []struct {
+		name          string
+		requestDelay  time.Duration
+		expectedError codes.Code
+	}{
+		{"in-flight call completes within the timeout", 100 * time.Millisecond, codes.OK},
+		{"in-flight call is canceled after the timeout", 1 * time.Minute, codes.Unavailable},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			require := require.New(t)
+
+			started := make(chan struct{}, 1)
+			s, err := (&GRPCServerConfig{
+				Network:             BufferedNetwork,
+				Enabled:             true,
+				GracefulStopTimeout: 1 * time.Second,
+			}).Complete(zerolog.InfoLevel, func(*grpc.Server) {}, grpc.UnknownServiceHandler(slowHandler(started)))
+			require.NoError(err)
+
+			go func() {
+				_ = s.Listen(context.Background())()
+			}()
+
+			conn, err := s.DialContext(context.Background(), grpc.WithBlock())
+			require.NoError(err)
+			t.Cleanup(func() { _ = conn.Close() })
+
+			inFlight := make(chan error, 1)
+			go func() {
+				inFlight <- conn.Invoke(context.Background(), "/test.Slow/Call", durationpb.New(tc.requestDelay), &emptypb.Empty{})
+			}()
+
+			// Wait for the call to be in-flight before stopping.
+			<-started
+
+			stopped := make(chan struct{})
+			go func() {
+				s.GracefulStop()
+				close(stopped)
+			}()
+
+			// New calls are rejected once the server is stopping.
+			require.Eventually(func() bool {
+				err := conn.Invoke(context.Background(), "/test.Slow/Call", durationpb.New(0), &emptypb.Empty{})
+				return status.Code(err) == codes.Unavailable
+			}, 1*time.Second, 10*time.Millisecond)
+
+			require.Equal(tc.expectedError, status.Code(<-inFlight))
+
+			select {
+			case <-stopped:
+			case <-time.After(5 * time.Second):
+				require.Fail("server did not stop")
+			}
+		})
+	}
+}
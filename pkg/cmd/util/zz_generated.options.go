@@ -40,6 +40,7 @@ func (g *GRPCServerConfig) ToOption() GRPCServerConfigOption {
 		to.BufferSize = g.BufferSize
 		to.ClientCAPath = g.ClientCAPath
 		to.MaxWorkers = g.MaxWorkers
+		to.GracefulStopTimeout = g.GracefulStopTimeout
 		to.flagPrefix = g.flagPrefix
 	}
 }
@@ -56,6 +57,7 @@ func (g GRPCServerConfig) DebugMap() map[string]any {
 	debugMap["BufferSize"] = helpers.DebugValue(g.BufferSize, false)
 	debugMap["ClientCAPath"] = helpers.DebugValue(g.ClientCAPath, false)
 	debugMap["MaxWorkers"] = helpers.DebugValue(g.MaxWorkers, false)
+	debugMap["GracefulStopTimeout"] = helpers.DebugValue(g.GracefulStopTimeout, false)
 	return debugMap
 }
 
@@ -138,6 +140,13 @@ func WithMaxWorkers(maxWorkers uint32) GRPCServerConfigOption {
 	}
 }
 
+// WithGracefulStopTimeout returns an option that can set GracefulStopTimeout on a GRPCServerConfig
+func WithGracefulStopTimeout(gracefulStopTimeout time.Duration) GRPCServerConfigOption {
+	return func(g *GRPCServerConfig) {
+		g.GracefulStopTimeout = gracefulStopTimeout
+	}
+}
+
 type HTTPServerConfigOption func(h *HTTPServerConfig)
 
 // NewHTTPServerConfigWithOptions creates a new HTTPServerConfig with the passed in options set
@@ -1,8 +1,12 @@
 package datastore
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/authzed/spicedb/pkg/namespace"
 	iv1 "github.com/authzed/spicedb/pkg/proto/impl/v1"
+	"github.com/authzed/spicedb/pkg/tuple"
 )
 
 // ComputeObjectTypeStats creates a list of object type stats from an input list of
@@ -29,3 +33,36 @@ func ComputeObjectTypeStats(objTypes []RevisionedNamespace) []ObjectTypeStat {
 
 	return stats
 }
+
+// ComputeRelationCounts counts the relationships found by the reader, grouped by the
+// resource type and relation of each relationship and keyed by `namespace#relation`.
+// Unlike the estimate returned by Statistics, the counts are exact and computed by
+// reading every relationship, so this should not be used on large datastores.
+func ComputeRelationCounts(ctx context.Context, reader Reader) (map[string]uint64, error) {
+	objTypes, err := reader.ListAllNamespaces(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list object types: %w", err)
+	}
+
+	counts := make(map[string]uint64)
+	for _, objType := range objTypes {
+		it, err := reader.QueryRelationships(ctx, RelationshipsFilter{
+			ResourceType: objType.Definition.Name,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to query relationships: %w", err)
+		}
+
+		for tpl := it.Next(); tpl != nil; tpl = it.Next() {
+			counts[tuple.JoinRelRef(tpl.ResourceAndRelation.Namespace, tpl.ResourceAndRelation.Relation)]++
+		}
+
+		err = it.Err()
+		it.Close()
+		if err != nil {
+			return nil, fmt.Errorf("unable to count relationships: %w", err)
+		}
+	}
+
+	return counts, nil
+}
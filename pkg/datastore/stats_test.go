@@ -0,0 +1,64 @@
+package datastore_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/authzed/spicedb/internal/datastore/common"
+	"github.com/authzed/spicedb/internal/datastore/memdb"
+	tf "github.com/authzed/spicedb/internal/testfixtures"
+	"github.com/authzed/spicedb/pkg/datastore"
+	core "github.com/authzed/spicedb/pkg/proto/core/v1"
+	"github.com/authzed/spicedb/pkg/tuple"
+)
+
+func TestComputeRelationCounts(t *testing.T) {
+	require := require.New(t)
+
+	rawDS, err := memdb.NewMemdbDatastore(0, 0, memdb.DisableGC)
+	require.NoError(err)
+
+	ds, revision := tf.DatastoreFromSchemaAndTestRelationships(rawDS, `
+		definition user {}
+
+		definition document {
+			relation viewer: user
+			relation editor: user
+			permission view = viewer + editor
+		}
+
+		definition folder {
+			relation viewer: user
+		}
+	`, []*core.RelationTuple{
+		tuple.MustParse("document:first#viewer@user:tom"),
+		tuple.MustParse("document:first#viewer@user:fred"),
+		tuple.MustParse("document:second#viewer@user:tom"),
+		tuple.MustParse("document:first#editor@user:sarah"),
+		tuple.MustParse("folder:root#viewer@user:tom"),
+	}, require)
+
+	counts, err := datastore.ComputeRelationCounts(context.Background(), ds.SnapshotReader(revision))
+	require.NoError(err)
+	require.Equal(map[string]uint64{
+		"document#viewer": 3,
+		"document#editor": 1,
+		"folder#viewer":   1,
+	}, counts)
+
+	// Counts are computed at the revision of the reader.
+	updatedRevision, err := common.WriteTuples(context.Background(), ds, core.RelationTupleUpdate_DELETE,
+		tuple.MustParse("document:first#viewer@user:fred"),
+	)
+	require.NoError(err)
+
+	counts, err = datastore.ComputeRelationCounts(context.Background(), ds.SnapshotReader(updatedRevision))
+	require.NoError(err)
+	require.Equal(uint64(2), counts["document#viewer"])
+
+	counts, err = datastore.ComputeRelationCounts(context.Background(), ds.SnapshotReader(revision))
+	require.NoError(err)
+	require.Equal(uint64(3), counts["document#viewer"])
+}
@@ -286,7 +286,13 @@ func loadCompiled(
 			continue
 		}
 
-		_, tverr := ts.Validate(ctx)
+		vts, tverr := ts.Validate(ctx)
+		if tverr == nil {
+			// Annotate the namespace to ensure the same checks as a schema write, such as
+			// cycles between permissions, are applied.
+			tverr = namespace.AnnotateNamespace(vts)
+		}
+
 		if tverr == nil {
 			if err := rwt.WriteNamespaces(ctx, nsDef); err != nil {
 				return errors, err
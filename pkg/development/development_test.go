@@ -10,6 +10,7 @@ import (
 
 	core "github.com/authzed/spicedb/pkg/proto/core/v1"
 	devinterface "github.com/authzed/spicedb/pkg/proto/developer/v1"
+	"github.com/authzed/spicedb/pkg/testutil"
 	"github.com/authzed/spicedb/pkg/tuple"
 	"github.com/authzed/spicedb/pkg/validationfile/blocks"
 )
@@ -140,3 +141,167 @@ definition document {
 
 	shutdown()
 }
+
+func TestDevelopmentSchemaErrorPositions(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreTopFunction("github.com/golang/glog.(*loggingT).flushDaemon"), goleak.IgnoreCurrent())
+
+	testCases := []struct {
+		name        string
+		schema      string
+		expectedErr *devinterface.DeveloperError
+	}{
+		{
+			"parse error",
+			`definition user {}
+
+definition document {
+	relation viewer: user
+	permission view = viewer +
+}`,
+			&devinterface.DeveloperError{
+				Message: "Expected right hand expression, found: TokenTypeRightBrace",
+				Kind:    devinterface.DeveloperError_SCHEMA_ISSUE,
+				Source:  devinterface.DeveloperError_SCHEMA,
+				Line:    6,
+				Column:  1,
+				Context: "}",
+			},
+		},
+		{
+			"permission references undefined relation",
+			`definition user {}
+
+definition document {
+	relation viewer: user
+	permission view = viewer + editor
+}`,
+			&devinterface.DeveloperError{
+				Message: "relation/permission `editor` not found under definition `document`",
+				Kind:    devinterface.DeveloperError_SCHEMA_ISSUE,
+				Source:  devinterface.DeveloperError_SCHEMA,
+				Line:    5,
+				Column:  29,
+				Context: "editor",
+			},
+		},
+		{
+			"arrow references undefined relation",
+			`definition user {}
+
+definition document {
+	relation viewer: user
+	permission view = viewer + parent->view
+}`,
+			&devinterface.DeveloperError{
+				Message: "relation/permission `parent` not found under definition `document`",
+				Kind:    devinterface.DeveloperError_SCHEMA_ISSUE,
+				Source:  devinterface.DeveloperError_SCHEMA,
+				Line:    5,
+				Column:  29,
+				Context: "parent",
+			},
+		},
+		{
+			"allowed type references undefined definition",
+			`definition user {}
+
+definition document {
+	relation viewer: user | group#member
+}`,
+			&devinterface.DeveloperError{
+				Message: "could not lookup definition `group` for relation `viewer`: object definition `group` not found",
+				Kind:    devinterface.DeveloperError_SCHEMA_ISSUE,
+				Source:  devinterface.DeveloperError_SCHEMA,
+				Line:    4,
+				Column:  26,
+				Context: "group",
+			},
+		},
+		{
+			"allowed type references undefined relation",
+			`definition user {}
+
+definition group {
+	relation member: user
+}
+
+definition document {
+	relation viewer: user | group#admin
+}`,
+			&devinterface.DeveloperError{
+				Message: "relation/permission `admin` not found under definition `group`",
+				Kind:    devinterface.DeveloperError_SCHEMA_ISSUE,
+				Source:  devinterface.DeveloperError_SCHEMA,
+				Line:    8,
+				Column:  26,
+				Context: "admin",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, devErrs, err := NewDevContext(context.Background(), &devinterface.RequestContext{
+				Schema: tc.schema,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, devErrs)
+			require.Len(t, devErrs.InputErrors, 1)
+			testutil.RequireProtoEqual(t, tc.expectedErr, devErrs.InputErrors[0], "mismatch in developer error")
+		})
+	}
+}
+
+func TestDevelopmentPermissionsCycle(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreTopFunction("github.com/golang/glog.(*loggingT).flushDaemon"), goleak.IgnoreCurrent())
+
+	_, devErrs, err := NewDevContext(context.Background(), &devinterface.RequestContext{
+		Schema: `definition user {}
+
+definition document {
+	relation viewer: user
+	permission view = edit
+	permission edit = view
+}`,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, devErrs)
+	require.Len(t, devErrs.InputErrors, 1)
+	testutil.RequireProtoEqual(t, &devinterface.DeveloperError{
+		Message: "under definition `document`, there exists a cycle in permissions: edit, view",
+		Kind:    devinterface.DeveloperError_SCHEMA_ISSUE,
+		Source:  devinterface.DeveloperError_SCHEMA,
+		Context: "document",
+	}, devErrs.InputErrors[0], "mismatch in developer error")
+}
+
+func TestDevelopmentSchemaMultipleErrors(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreTopFunction("github.com/golang/glog.(*loggingT).flushDaemon"), goleak.IgnoreCurrent())
+
+	_, devErrs, err := NewDevContext(context.Background(), &devinterface.RequestContext{
+		Schema: `definition user {}
+
+definition folder {
+	relation viewer: user
+	permission view = viewer + editor
+}
+
+definition document {
+	relation viewer: user
+	permission view = viewer + parent->view
+}`,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, devErrs)
+	require.Len(t, devErrs.InputErrors, 2)
+
+	messages := make([]string, 0, len(devErrs.InputErrors))
+	for _, devErr := range devErrs.InputErrors {
+		messages = append(messages, devErr.Message)
+	}
+	require.ElementsMatch(t, []string{
+		"relation/permission `editor` not found under definition `folder`",
+		"relation/permission `parent` not found under definition `document`",
+	}, messages)
+}
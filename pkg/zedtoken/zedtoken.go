@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"time"
 
 	v1 "github.com/authzed/authzed-go/proto/authzed/api/v1"
 
@@ -22,6 +23,10 @@ const (
 // zedtoken argument to Decode
 var ErrNilZedToken = errors.New("zedtoken pointer was nil")
 
+// ErrRevisionWithoutTimestamp is returned by DecodeRevisionTimestamp when the
+// datastore's revisions do not carry a commit timestamp.
+var ErrRevisionWithoutTimestamp = errors.New("revision does not contain a timestamp")
+
 // MustNewFromRevision generates an encoded zedtoken from an integral revision.
 func MustNewFromRevision(revision datastore.Revision) *v1.ZedToken {
 	encoded, err := NewFromRevision(revision)
@@ -103,6 +108,50 @@ func DecodeRevision(encoded *v1.ZedToken, ds revisionDecoder) (datastore.Revisio
 	}
 }
 
+// Compare decodes the revisions of the two zedtokens and compares them, returning -1 if the
+// revision of a is older than that of b, 0 if they are the same revision and 1 if the revision
+// of a is newer than that of b. Returns an error if either zedtoken cannot be decoded into a
+// revision for the datastore.
+func Compare(a, b *v1.ZedToken, ds revisionDecoder) (int, error) {
+	aRevision, err := DecodeRevision(a, ds)
+	if err != nil {
+		return 0, err
+	}
+
+	bRevision, err := DecodeRevision(b, ds)
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case aRevision.LessThan(bRevision):
+		return -1, nil
+	case aRevision.GreaterThan(bRevision):
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// DecodeRevisionTimestamp extracts the revision from a zedtoken and returns the
+// approximate wall-clock time at which that revision was committed.
+func DecodeRevisionTimestamp(encoded *v1.ZedToken, ds revisionDecoder) (time.Time, error) {
+	revision, err := DecodeRevision(encoded, ds)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	withTimestamp, ok := revision.(timestampedRevision)
+	if !ok {
+		return time.Time{}, fmt.Errorf(errDecodeError, ErrRevisionWithoutTimestamp)
+	}
+	return time.Unix(0, withTimestamp.TimestampNanoSec()), nil
+}
+
+type timestampedRevision interface {
+	TimestampNanoSec() int64
+}
+
 type revisionDecoder interface {
 	RevisionFromString(string) (datastore.Revision, error)
 }
@@ -1,8 +1,10 @@
 package zedtoken
 
 import (
+	"encoding/base64"
 	"fmt"
 	"testing"
+	"time"
 
 	v1 "github.com/authzed/authzed-go/proto/authzed/api/v1"
 	"github.com/shopspring/decimal"
@@ -211,3 +213,104 @@ func TestHLCDecode(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeRevisionTimestamp(t *testing.T) {
+	committedAt := time.Date(2023, time.March, 14, 15, 9, 26, 535897932, time.UTC)
+
+	testCases := []struct {
+		name              string
+		revision          datastore.Revision
+		kind              revisions.RevisionKind
+		expectedTimestamp time.Time
+		expectedError     error
+	}{
+		{
+			"timestamp revision",
+			revisions.NewForTime(committedAt),
+			revisions.Timestamp,
+			committedAt,
+			nil,
+		},
+		{
+			"hlc revision",
+			revisions.NewHLCForTime(committedAt),
+			revisions.HybridLogicalClock,
+			committedAt,
+			nil,
+		},
+		{
+			"transaction id revision",
+			revisions.NewForTransactionID(42),
+			revisions.TransactionID,
+			time.Time{},
+			ErrRevisionWithoutTimestamp,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			require := require.New(t)
+
+			encoded, err := NewFromRevision(tc.revision)
+			require.NoError(err)
+
+			timestamp, err := DecodeRevisionTimestamp(encoded, revisions.CommonDecoder{Kind: tc.kind})
+			if tc.expectedError != nil {
+				require.ErrorIs(err, tc.expectedError)
+				return
+			}
+
+			require.NoError(err)
+			require.True(tc.expectedTimestamp.Equal(timestamp), "%s != %s", tc.expectedTimestamp, timestamp)
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	testCases := []struct {
+		name           string
+		a              datastore.Revision
+		b              datastore.Revision
+		kind           revisions.RevisionKind
+		expectedResult int
+		expectError    bool
+	}{
+		{"equal", revisions.NewForTransactionID(42), revisions.NewForTransactionID(42), revisions.TransactionID, 0, false},
+		{"older", revisions.NewForTransactionID(41), revisions.NewForTransactionID(42), revisions.TransactionID, -1, false},
+		{"newer", revisions.NewForTransactionID(43), revisions.NewForTransactionID(42), revisions.TransactionID, 1, false},
+		{"equal hlc", mustHLC("1234.0000000001"), mustHLC("1234.0000000001"), revisions.HybridLogicalClock, 0, false},
+		{"older hlc", mustHLC("1234.0000000001"), mustHLC("1234.0000000002"), revisions.HybridLogicalClock, -1, false},
+		{"newer hlc", mustHLC("1235"), mustHLC("1234.0000000002"), revisions.HybridLogicalClock, 1, false},
+		{"token from another kind of datastore", mustHLC("1234.0000000001"), revisions.NewForTransactionID(42), revisions.TransactionID, 0, true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			require := require.New(t)
+
+			result, err := Compare(MustNewFromRevision(tc.a), MustNewFromRevision(tc.b), revisions.CommonDecoder{Kind: tc.kind})
+			if tc.expectError {
+				require.Error(err)
+				return
+			}
+
+			require.NoError(err)
+			require.Equal(tc.expectedResult, result)
+		})
+	}
+}
+
+func TestDecodeRevisionRejectsUnknownVersion(t *testing.T) {
+	require := require.New(t)
+
+	// A token written by a newer server with a version (field 4 of the
+	// version_oneof) unknown to this decoder: {4: {1: "1"}}.
+	token := base64.StdEncoding.EncodeToString([]byte{0x22, 0x03, 0x0a, 0x01, 0x31})
+
+	_, err := DecodeRevision(&v1.ZedToken{Token: token}, revisions.CommonDecoder{
+		Kind: revisions.TransactionID,
+	})
+	require.ErrorContains(err, "unknown zookie version")
+}